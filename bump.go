@@ -0,0 +1,188 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrManualVersioning  = errors.New("versioningStrategy is manual")
+	ErrVersionRegression = errors.New("version would go backwards")
+)
+
+// WithPrereleaseIdentifier makes Bump produce a prerelease with the given identifier, for example "beta".
+func WithPrereleaseIdentifier(preid string) Option {
+	return func(o *options) {
+		o.prereleaseIdentifier = preid
+	}
+}
+
+// WithBuildMetadata adds build metadata, for example "build.5", to the version produced by Bump.
+func WithBuildMetadata(build string) Option {
+	return func(o *options) {
+		o.buildMetadata = build
+	}
+}
+
+// Bump increments the version of the target language in gen.yaml and the release version in gen.lock, returning the new version.
+// Configs with a manual versioningStrategy are left unchanged and ErrManualVersioning is returned.
+// The new version must be higher than both the current version and the last released version, otherwise ErrVersionRegression is returned.
+func (c *Config) Bump(target string, level BumpLevel, opts ...Option) (string, error) {
+	o := applyOptions(opts)
+
+	if c.Config.Generation.VersioningStrategy == VersioningStrategyManual {
+		return "", fmt.Errorf("%w, update the %s version in gen.yaml instead", ErrManualVersioning, target)
+	}
+
+	langCfg, ok := c.Config.Languages[target]
+	if !ok {
+		return "", fmt.Errorf("%s is not configured in gen.yaml", target)
+	}
+
+	current := SemVer{}
+	if langCfg.Version != "" {
+		var err error
+		current, err = ParseSemVer(langCfg.Version)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	next, err := current.Bump(level, o.prereleaseIdentifier)
+	if err != nil {
+		return "", err
+	}
+
+	if o.buildMetadata != "" {
+		build, err := parseIdentifiers(o.buildMetadata, false)
+		if err != nil {
+			return "", fmt.Errorf("%w: build metadata %w", ErrInvalidVersion, err)
+		}
+		next.Build = build
+	}
+
+	if next.Compare(current) <= 0 {
+		return "", fmt.Errorf("%w: %s is not higher than %s", ErrVersionRegression, next, current)
+	}
+
+	if c.LockFile != nil && c.LockFile.Management.ReleaseVersion != "" {
+		released, err := ParseSemVer(c.LockFile.Management.ReleaseVersion)
+		if err != nil {
+			return "", fmt.Errorf("could not parse releaseVersion in gen.lock: %w", err)
+		}
+		if next.Compare(released) <= 0 {
+			return "", fmt.Errorf("%w: %s is not higher than the released version %s", ErrVersionRegression, next, released)
+		}
+	}
+
+	version := next.String()
+
+	langCfg.Version = version
+	c.Config.Languages[target] = langCfg
+	if c.LockFile != nil {
+		c.LockFile.Management.ReleaseVersion = version
+	}
+
+	return version, nil
+}
+
+// BumpVersion loads the config in dir, bumps the version of the target language and saves it to gen.yaml and gen.lock.
+// If the config is loaded WithLock the workspace is locked until both files are written, waiting for another process to
+// release it. Only the version fields are written, so defaults filled in while loading aren't saved.
+func BumpVersion(dir, target string, level BumpLevel, opts ...Option) (string, error) {
+	o := applyOptions(opts)
+
+	configRes, err := FindConfigFile(dir, o.FS)
+	if err != nil {
+		return "", err
+	}
+	if o.lock {
+		release, err := acquireLock(configRes.Path, o)
+		if err != nil {
+			return "", err
+		}
+		defer release()
+
+		// The lock is already held, so loading mustn't try to take it again
+		opts = append(slices.Clone(opts), withoutLock())
+	}
+
+	cfg, err := Load(dir, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	version, err := cfg.Bump(target, level, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	// Read the files again as loading may have written them
+	configRes, err = FindConfigFile(dir, o.FS)
+	if err != nil {
+		return "", err
+	}
+	if err := writeVersion(configRes.Path, configRes.Data, []string{target, "version"}, version, cfg.Config, o); err != nil {
+		return "", err
+	}
+
+	lockFileRes, err := findLockFile(dir, o)
+	if err != nil {
+		return "", err
+	}
+	if err := writeVersion(lockFileRes.Path, lockFileRes.Data, []string{"management", "releaseVersion"}, version, cfg.LockFile, o); err != nil {
+		return "", err
+	}
+
+	return version, nil
+}
+
+// writeVersion sets the version at path in the YAML document held in original and writes it, leaving the rest of the
+// document untouched. If there is no document yet cfg is written in full.
+func writeVersion(filePath string, original []byte, path []string, version string, cfg any, o *options) error {
+	var doc yaml.Node
+	if len(bytes.TrimSpace(original)) == 0 {
+		_, err := write(filePath, cfg, nil, o)
+		return err
+	}
+	if err := yaml.Unmarshal(original, &doc); err != nil {
+		return fmt.Errorf("could not parse %s: %w", filePath, err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return fmt.Errorf("could not parse %s: expected a mapping", filePath)
+	}
+
+	n := doc.Content[0]
+	for i, key := range path {
+		var child *yaml.Node
+		for j := 0; j+1 < len(n.Content); j += 2 {
+			if n.Content[j].Value == key {
+				child = n.Content[j+1]
+				break
+			}
+		}
+
+		if child == nil {
+			child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			if i == len(path)-1 {
+				child = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str"}
+			}
+			n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, child)
+		} else if i < len(path)-1 && child.Kind != yaml.MappingNode {
+			return fmt.Errorf("could not set %s in %s: %s is not a mapping", strings.Join(path, "."), filePath, key)
+		}
+		n = child
+	}
+
+	n.Kind = yaml.ScalarNode
+	n.Tag = "!!str"
+	n.Value = version
+
+	_, err := write(filePath, &doc, original, o)
+	return err
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/speakeasy-api/sdk-gen-config/lockfile"
+	"github.com/speakeasy-api/sdk-gen-config/testutils"
+	"github.com/speakeasy-api/sdk-gen-config/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Bump(t *testing.T) {
+	tests := []struct {
+		name           string
+		version        string
+		releaseVersion string
+		strategy       VersioningStrategy
+		level          BumpLevel
+		opts           []Option
+		want           string
+		wantErr        error
+	}{
+		{
+			name:           "bumps the version and release version",
+			version:        "1.3.0",
+			releaseVersion: "1.3.0",
+			level:          BumpMinor,
+			want:           "1.4.0",
+		},
+		{
+			name:    "bumps to a prerelease with build metadata",
+			version: "1.3.0",
+			level:   BumpMajor,
+			opts:    []Option{WithPrereleaseIdentifier("beta"), WithBuildMetadata("build.7")},
+			want:    "2.0.0-beta.0+build.7",
+		},
+		{
+			name:    "bumps an unset version",
+			version: "",
+			level:   BumpPatch,
+			want:    "0.0.1",
+		},
+		{
+			name:     "refuses to bump manually versioned configs",
+			version:  "1.3.0",
+			strategy: VersioningStrategyManual,
+			level:    BumpPatch,
+			wantErr:  ErrManualVersioning,
+		},
+		{
+			name:           "refuses to go below the released version",
+			version:        "1.3.0",
+			releaseVersion: "1.5.0",
+			level:          BumpPatch,
+			wantErr:        ErrVersionRegression,
+		},
+		{
+			name:    "refuses to go below the current prerelease",
+			version: "1.3.0-rc.1",
+			level:   BumpPrerelease,
+			opts:    []Option{WithPrereleaseIdentifier("beta")},
+			wantErr: ErrVersionRegression,
+		},
+		{
+			name:    "rejects invalid versions",
+			version: "one",
+			level:   BumpPatch,
+			wantErr: ErrInvalidVersion,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Config: &Configuration{
+					Generation: Generation{VersioningStrategy: tt.strategy},
+					Languages: map[string]LanguageConfig{
+						"go": {Version: tt.version},
+					},
+				},
+				LockFile: &LockFile{
+					Management: Management{ReleaseVersion: tt.releaseVersion},
+				},
+			}
+
+			got, err := cfg.Bump("go", tt.level, tt.opts...)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, tt.version, cfg.Config.Languages["go"].Version)
+				assert.Equal(t, tt.releaseVersion, cfg.LockFile.Management.ReleaseVersion)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.want, cfg.Config.Languages["go"].Version)
+			assert.Equal(t, tt.want, cfg.LockFile.Management.ReleaseVersion)
+		})
+	}
+}
+
+func TestBumpVersion(t *testing.T) {
+	getUUID = func() string {
+		return "123"
+	}
+	lockfile.GetUUID = getUUID
+
+	dir := t.TempDir()
+	speakeasyDir := filepath.Join(dir, ".speakeasy")
+	testutils.CreateTempFile(t, speakeasyDir, "gen.yaml", testutils.ReadTestFile(t, "v200-gen.yaml"))
+	testutils.CreateTempFile(t, speakeasyDir, "gen.lock", testutils.ReadTestFile(t, "v200-gen.lock"))
+
+	version, err := BumpVersion(dir, "go", BumpMinor, WithLanguages("go"))
+	require.NoError(t, err)
+	assert.Equal(t, "1.4.0", version)
+
+	cfg, err := Load(dir, WithLanguages("go"))
+	require.NoError(t, err)
+	assert.Equal(t, "1.4.0", cfg.Config.Languages["go"].Version)
+	assert.Equal(t, "1.4.0", cfg.LockFile.Management.ReleaseVersion)
+
+	// Only the versions change, defaults filled in by Load aren't written
+	data, err := os.ReadFile(filepath.Join(speakeasyDir, "gen.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, strings.Replace(testutils.ReadTestFile(t, "v200-gen.yaml"), "version: 1.3.0", "version: 1.4.0", 1), string(data))
+
+	data, err = os.ReadFile(filepath.Join(speakeasyDir, "gen.lock"))
+	require.NoError(t, err)
+	assert.Equal(t, strings.Replace(testutils.ReadTestFile(t, "v200-gen.lock"), "releaseVersion: 1.3.0", "releaseVersion: 1.4.0", 1), string(data))
+}
+
+func TestBumpVersion_Locked(t *testing.T) {
+	dir := t.TempDir()
+	speakeasyDir := filepath.Join(dir, ".speakeasy")
+	testutils.CreateTempFile(t, speakeasyDir, "gen.yaml", testutils.ReadTestFile(t, "v200-gen.yaml"))
+	testutils.CreateTempFile(t, speakeasyDir, "gen.lock", testutils.ReadTestFile(t, "v200-gen.lock"))
+
+	lock, err := workspace.AcquireLock(speakeasyDir, 0)
+	require.NoError(t, err)
+
+	_, err = BumpVersion(dir, "go", BumpMinor, WithLanguages("go"), WithLock(100*time.Millisecond))
+	assert.ErrorIs(t, err, workspace.ErrLocked)
+
+	// The lock is only taken WithLock
+	version, err := BumpVersion(dir, "go", BumpMinor, WithLanguages("go"))
+	require.NoError(t, err)
+	assert.Equal(t, "1.4.0", version)
+
+	require.NoError(t, lock.Release())
+
+	version, err = BumpVersion(dir, "go", BumpMinor, WithLanguages("go"), WithLock(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, "1.5.0", version)
+
+	_, err = os.Stat(filepath.Join(speakeasyDir, workspace.LockFileName))
+	assert.ErrorIs(t, err, os.ErrNotExist, "the lock is released")
+}
@@ -0,0 +1,151 @@
+package config
+
+import (
+	"crypto/md5"
+	"crypto/sha1" // nolint:gosec // sha1 is offered for consistency with the lockfile checksums
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+
+	"gopkg.in/yaml.v3"
+)
+
+type ChecksumAlgorithm string
+
+const (
+	ChecksumMD5    ChecksumAlgorithm = "md5"
+	ChecksumSHA1   ChecksumAlgorithm = "sha1"
+	ChecksumSHA256 ChecksumAlgorithm = "sha256"
+)
+
+// WithChecksumAlgorithm sets the hash used by GetCanonicalConfigChecksum, defaults to ChecksumSHA256.
+func WithChecksumAlgorithm(alg ChecksumAlgorithm) Option {
+	return func(o *options) {
+		o.checksumAlgorithm = alg
+	}
+}
+
+// GetCanonicalConfigChecksum returns a checksum of the parsed gen.yaml, merged with any configs it extends and
+// gen.local.yaml, in the form "<algorithm>:<hex>".
+// Unlike GetConfigChecksum it is computed over a canonical form of the Configuration, with defaults applied,
+// keys sorted and scalars normalized, so reordering keys, reindenting or editing comments doesn't change it.
+// The languages and language defaults should be provided with WithLanguages and WithLanguageDefaultFunc
+// as they are to Load.
+func GetCanonicalConfigChecksum(dir string, opts ...Option) (string, error) {
+	o := applyOptions(opts)
+
+	configRes, err := FindConfigFile(dir, o.FS)
+	if err != nil {
+		return "", err
+	}
+	if configRes.Data == nil {
+		return "", nil
+	}
+
+	newHash, err := checksumHash(o.checksumAlgorithm)
+	if err != nil {
+		return "", err
+	}
+
+	// Hash the config as Load resolves it, so editing a config it extends or gen.local.yaml changes the checksum
+	layers, err := loadConfigLayers(configRes.Path, configRes.Data, o)
+	if err != nil {
+		return "", err
+	}
+	data := configRes.Data
+	if layers.layered() {
+		data, err = yaml.Marshal(layers.merged())
+		if err != nil {
+			return "", fmt.Errorf("could not merge gen.yaml: %w", err)
+		}
+	}
+
+	canonical, err := canonicalConfig(data, o)
+	if err != nil {
+		return "", err
+	}
+
+	h := newHash()
+	h.Write(canonical)
+
+	return string(o.checksumAlgorithm) + ":" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func checksumHash(alg ChecksumAlgorithm) (func() hash.Hash, error) {
+	switch alg {
+	case ChecksumMD5:
+		return md5.New, nil
+	case ChecksumSHA1:
+		return sha1.New, nil
+	case ChecksumSHA256:
+		return sha256.New, nil
+	default:
+		return nil, fmt.Errorf("unsupported checksum algorithm %q", alg)
+	}
+}
+
+// canonicalConfig returns the config in data as JSON with sorted keys, after applying the defaults for an existing SDK.
+func canonicalConfig(data []byte, o *options) ([]byte, error) {
+	requiredDefaults := map[string]bool{}
+	for _, lang := range o.langs {
+		requiredDefaults[lang] = false
+	}
+
+	cfg, err := GetDefaultConfig(false, o.getLanguageDefaultFunc, requiredDefaults)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("could not unmarshal gen.yaml: %w", err)
+	}
+
+	out, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal gen.yaml: %w", err)
+	}
+
+	var m map[string]any
+	if err := yaml.Unmarshal(out, &m); err != nil {
+		return nil, fmt.Errorf("could not unmarshal gen.yaml: %w", err)
+	}
+
+	// encoding/json sorts map keys
+	return json.Marshal(normalizeValue(m))
+}
+
+// normalizeValue drops empty values and represents all numbers as float64, so that equivalent spellings hash the same.
+func normalizeValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		out := map[string]any{}
+		for k, child := range val {
+			if n := normalizeValue(child); n != nil {
+				out[k] = n
+			}
+		}
+		if len(out) == 0 {
+			return nil
+		}
+		return out
+	case []any:
+		if len(val) == 0 {
+			return nil
+		}
+		out := make([]any, len(val))
+		for i, child := range val {
+			out[i] = normalizeValue(child)
+		}
+		return out
+	case int:
+		return float64(val)
+	case int64:
+		return float64(val)
+	case uint64:
+		return float64(val)
+	default:
+		return val
+	}
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/speakeasy-api/sdk-gen-config/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCanonicalConfigChecksum_Success(t *testing.T) {
+	base := `configVersion: 2.0.0
+generation:
+  sdkClassName: speakeasy
+  maintainOpenAPIOrder: true
+go:
+  version: 1.0.0
+  maxMethodParams: 4
+`
+
+	tests := []struct {
+		name      string
+		genYaml   string
+		wantEqual bool
+	}{
+		{
+			name:      "identical config",
+			genYaml:   base,
+			wantEqual: true,
+		},
+		{
+			name: "reordered keys, comments and indentation",
+			genYaml: `# a comment
+go:
+    maxMethodParams: 4.0
+    version: "1.0.0"
+generation:
+    maintainOpenAPIOrder: true # inline
+    sdkClassName: 'speakeasy'
+configVersion: 2.0.0
+`,
+			wantEqual: true,
+		},
+		{
+			name:      "explicit default values",
+			genYaml:   strings.Replace(base, "go:\n", "  usageSnippets:\n    sdkInitStyle: constructor\ngo:\n  additionalDependencies: {}\n", 1),
+			wantEqual: true,
+		},
+		{
+			name:      "changed value",
+			genYaml:   strings.Replace(base, "speakeasy", "MySDK", 1),
+			wantEqual: false,
+		},
+		{
+			name:      "changed language value",
+			genYaml:   strings.Replace(base, "maxMethodParams: 4", "maxMethodParams: 5", 1),
+			wantEqual: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseDir := t.TempDir()
+			testutils.CreateTempFile(t, filepath.Join(baseDir, ".speakeasy"), "gen.yaml", base)
+			dir := t.TempDir()
+			testutils.CreateTempFile(t, filepath.Join(dir, ".speakeasy"), "gen.yaml", tt.genYaml)
+
+			want, err := GetCanonicalConfigChecksum(baseDir, WithLanguages("go"))
+			require.NoError(t, err)
+			got, err := GetCanonicalConfigChecksum(dir, WithLanguages("go"))
+			require.NoError(t, err)
+
+			assert.True(t, strings.HasPrefix(got, "sha256:"))
+			if tt.wantEqual {
+				assert.Equal(t, want, got)
+			} else {
+				assert.NotEqual(t, want, got)
+			}
+
+			rawWant, err := GetConfigChecksum(baseDir)
+			require.NoError(t, err)
+			rawGot, err := GetConfigChecksum(dir)
+			require.NoError(t, err)
+			assert.Equal(t, tt.genYaml == base, rawWant == rawGot)
+		})
+	}
+}
+
+func TestGetCanonicalConfigChecksum_Algorithms(t *testing.T) {
+	dir := t.TempDir()
+	testutils.CreateTempFile(t, filepath.Join(dir, ".speakeasy"), "gen.yaml", testutils.ReadTestFile(t, "v200-gen.yaml"))
+
+	for alg, length := range map[ChecksumAlgorithm]int{
+		ChecksumMD5:    32,
+		ChecksumSHA1:   40,
+		ChecksumSHA256: 64,
+	} {
+		got, err := GetCanonicalConfigChecksum(dir, WithChecksumAlgorithm(alg))
+		require.NoError(t, err)
+		assert.Len(t, strings.TrimPrefix(got, string(alg)+":"), length)
+	}
+
+	_, err := GetCanonicalConfigChecksum(dir, WithChecksumAlgorithm("crc32"))
+	assert.EqualError(t, err, `unsupported checksum algorithm "crc32"`)
+}
+
+func TestGetCanonicalConfigChecksum_Layers(t *testing.T) {
+	dir := t.TempDir()
+	speakeasyDir := filepath.Join(dir, ".speakeasy")
+	testutils.CreateTempFile(t, speakeasyDir, "base.yaml", `generation:
+  sdkClassName: Shared
+`)
+	testutils.CreateTempFile(t, speakeasyDir, "gen.yaml", `configVersion: 2.0.0
+extends:
+  - base.yaml
+go:
+  version: 1.0.0
+`)
+
+	checksum := func() string {
+		t.Helper()
+		got, err := GetCanonicalConfigChecksum(dir, WithLanguages("go"))
+		require.NoError(t, err)
+		return got
+	}
+
+	initial := checksum()
+
+	// Editing a config that gen.yaml extends changes the checksum
+	testutils.CreateTempFile(t, speakeasyDir, "base.yaml", `generation:
+  sdkClassName: Renamed
+`)
+	extended := checksum()
+	assert.NotEqual(t, initial, extended)
+
+	// As does gen.local.yaml
+	testutils.CreateTempFile(t, speakeasyDir, "gen.local.yaml", `go:
+  maxMethodParams: 2
+`)
+	assert.NotEqual(t, extended, checksum())
+}
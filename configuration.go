@@ -1,7 +1,11 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/mitchellh/mapstructure"
@@ -58,22 +62,22 @@ func (ServerIndex) PrepareJSONSchema(schema *jsg.Schema) error {
 
 type UsageSnippets struct {
 	_                         struct{}                        `additionalProperties:"true" description:"Configuration for usage snippets"`
-	OptionalPropertyRendering OptionalPropertyRenderingOption `yaml:"optionalPropertyRendering" enum:"always,never,withExample" description:"Controls how optional properties are rendered in usage snippets"`
-	SDKInitStyle              SDKInitStyle                    `yaml:"sdkInitStyle" enum:"constructor,builder" description:"Controls how the SDK initialization is depicted in usage snippets"`
-	ServerToShowInSnippets    ServerIndex                     `yaml:"serverToShowInSnippets,omitempty"` // If unset, no server will be shown, if an integer, use as server_idx, else look for a matching id
-	AdditionalProperties      map[string]any                  `yaml:",inline" jsonschema:"-"`           // Captures any additional properties that are not explicitly defined for backwards/forwards compatibility
+	OptionalPropertyRendering OptionalPropertyRenderingOption `yaml:"optionalPropertyRendering" enum:"always,never,withExample" description:"Controls how optional properties are rendered in usage snippets, by default they will be rendered when an example is present in the OpenAPI spec" default:"withExample"`
+	SDKInitStyle              SDKInitStyle                    `yaml:"sdkInitStyle" enum:"constructor,builder" description:"Controls how the SDK initialization is depicted in usage snippets, by default it will use the constructor" default:"constructor"`
+	ServerToShowInSnippets    ServerIndex                     `yaml:"serverToShowInSnippets,omitempty" noDefault:"true" description:"Controls which server is shown in usage snippets. If unset, no server will be shown. If an integer, it will be used as the server index. Otherwise, it will look for a matching server ID."` // If unset, no server will be shown, if an integer, use as server_idx, else look for a matching id
+	AdditionalProperties      map[string]any                  `yaml:",inline" jsonschema:"-"`                                                                                                                                                                                                                                     // Captures any additional properties that are not explicitly defined for backwards/forwards compatibility
 }
 
 type Fixes struct {
 	_                                    struct{}       `additionalProperties:"true" description:"Fixes applied to the SDK generation"`
-	NameResolutionDec2023                bool           `yaml:"nameResolutionDec2023,omitempty" description:"Enables name resolution fixes from December 2023"`
-	NameResolutionFeb2025                bool           `yaml:"nameResolutionFeb2025" description:"Enables name resolution fixes from February 2025"`
-	ParameterOrderingFeb2024             bool           `yaml:"parameterOrderingFeb2024" description:"Enables parameter ordering fixes from February 2024"`
-	RequestResponseComponentNamesFeb2024 bool           `yaml:"requestResponseComponentNamesFeb2024" description:"Enables request and response component naming fixes from February 2024"`
-	SecurityFeb2025                      bool           `yaml:"securityFeb2025" description:"Enables fixes and refactoring for security that were introduced in February 2025"`
-	SharedErrorComponentsApr2025         bool           `yaml:"sharedErrorComponentsApr2025" description:"Enables fixes that mean that when a component is used in both 2XX and 4XX responses, only the top level component will be duplicated to the errors scope as opposed to the entire component tree"`
-	SharedNestedComponentsJan2026        bool           `yaml:"sharedNestedComponentsJan2026" description:"Fixes component naming when the same schema is referenced in multiple places within nested structures, ensuring consistent naming based on the original component definition"`
-	NameOverrideFeb2026                  bool           `yaml:"nameOverrideFeb2026" description:"Prevents component-level x-speakeasy-name-override from affecting parent names when referencing schema via $ref or hoisting allOf extensions"`
+	NameResolutionDec2023                bool           `yaml:"nameResolutionDec2023,omitempty" description:"Enables a number of breaking changes introduced in December 2023, that improve name resolution for inline schemas and reduce chances of name collisions" default:"false" newSDKDefault:"true"`
+	NameResolutionFeb2025                bool           `yaml:"nameResolutionFeb2025" description:"Enables a number of breaking changes introduced in February 2025, that improve name resolution for inline schemas and reduce chances of name collisions" default:"false" newSDKDefault:"true"`
+	ParameterOrderingFeb2024             bool           `yaml:"parameterOrderingFeb2024" description:"Enables fixes to the ordering of parameters for an operation if they include multiple types of parameters (ie header, query, path) to match the order they are defined in the OpenAPI spec" default:"false" newSDKDefault:"true"`
+	RequestResponseComponentNamesFeb2024 bool           `yaml:"requestResponseComponentNamesFeb2024" description:"Enables fixes that will name inline schemas within request and response components with the component name of the parent if only one content type is defined" default:"false" newSDKDefault:"true"`
+	SecurityFeb2025                      bool           `yaml:"securityFeb2025" description:"Enables fixes and refactoring for security that were introduced in February 2025" default:"false" newSDKDefault:"true"`
+	SharedErrorComponentsApr2025         bool           `yaml:"sharedErrorComponentsApr2025" description:"Enables fixes that mean that when a component is used in both 2XX and 4XX responses, only the top level component will be duplicated to the errors scope as opposed to the entire component tree" default:"false" newSDKDefault:"true"`
+	SharedNestedComponentsJan2026        bool           `yaml:"sharedNestedComponentsJan2026" description:"Fixes component naming when the same schema is referenced in multiple places within nested structures, ensuring consistent naming based on the original component definition" default:"false" newSDKDefault:"true"`
+	NameOverrideFeb2026                  bool           `yaml:"nameOverrideFeb2026" description:"Prevents component-level x-speakeasy-name-override from affecting parent names when referencing schema via $ref or hoisting allOf extensions" default:"false" newSDKDefault:"true"`
 	AdditionalProperties                 map[string]any `yaml:",inline" jsonschema:"-"` // Captures any additional properties that are not explicitly defined for backwards/forwards compatibility
 }
 
@@ -96,16 +100,16 @@ func (f *Fixes) UnmarshalYAML(unmarshal func(interface{}) error) error {
 
 type Auth struct {
 	_                              struct{} `additionalProperties:"false" description:"Authentication configuration"`
-	OAuth2ClientCredentialsEnabled bool     `yaml:"oAuth2ClientCredentialsEnabled" description:"Enables support for OAuth2 client credentials grant type"`
-	OAuth2PasswordEnabled          bool     `yaml:"oAuth2PasswordEnabled" description:"Enables support for OAuth2 resource owner password credentials grant type"`
-	HoistGlobalSecurity            bool     `yaml:"hoistGlobalSecurity" description:"Enables hoisting of operation-level security schemes to global level when no global security is defined"`
+	OAuth2ClientCredentialsEnabled bool     `yaml:"oAuth2ClientCredentialsEnabled" description:"Enables support for OAuth2 client credentials grant type (Enterprise tier only)" default:"false" newSDKDefault:"true"`
+	OAuth2PasswordEnabled          bool     `yaml:"oAuth2PasswordEnabled" description:"Enables support for OAuth2 resource owner password credentials grant type (Enterprise tier only)" default:"false" newSDKDefault:"true"`
+	HoistGlobalSecurity            bool     `yaml:"hoistGlobalSecurity" description:"Enables hoisting of operation-level security schemes to global level when no global security is defined" default:"true"`
 }
 
 type Tests struct {
 	_                          struct{}       `additionalProperties:"true" description:"Test generation configuration"`
-	GenerateTests              bool           `yaml:"generateTests" description:"Enables generation of tests"`
-	GenerateNewTests           bool           `yaml:"generateNewTests" description:"Enables generation of new tests for any new operations in the OpenAPI specification"`
-	SkipResponseBodyAssertions bool           `yaml:"skipResponseBodyAssertions" description:"Skip asserting that the client got the same response bodies returned by the mock server"`
+	GenerateTests              bool           `yaml:"generateTests" description:"Enables generation of tests" default:"true" newSDKDefault:"false"`
+	GenerateNewTests           bool           `yaml:"generateNewTests" description:"Enables generation of new tests for any new operations found in the OpenAPI spec" default:"false" newSDKDefault:"true"`
+	SkipResponseBodyAssertions bool           `yaml:"skipResponseBodyAssertions" description:"Skips the generation of response body assertions in tests" default:"false"`
 	AdditionalProperties       map[string]any `yaml:",inline" jsonschema:"-"` // Captures any additional properties that are not explicitly defined for backwards/forwards compatibility
 }
 
@@ -178,33 +182,35 @@ const (
 
 type Schemas struct {
 	_                  struct{}           `additionalProperties:"false" description:"Schema processing configuration"`
-	AllOfMergeStrategy AllOfMergeStrategy `yaml:"allOfMergeStrategy" enum:"deepMerge,shallowMerge" description:"Controls how allOf schemas are merged"`
+	AllOfMergeStrategy AllOfMergeStrategy `yaml:"allOfMergeStrategy" enum:"deepMerge,shallowMerge" description:"Controls how allOf schemas are merged, by default they will be merged using a shallow merge" default:"shallowMerge"`
 }
 
 type Generation struct {
 	_                           struct{}           `additionalProperties:"true" description:"Generation configuration"`
-	DevContainers               *DevContainers     `yaml:"devContainers,omitempty"`
-	BaseServerURL               string             `yaml:"baseServerUrl,omitempty" description:"The base URL of the server. This value will be used if global servers are not defined in the spec."`
-	SDKClassName                string             `yaml:"sdkClassName,omitempty" description:"Generated name of the root SDK class"`
-	MaintainOpenAPIOrder        bool               `yaml:"maintainOpenAPIOrder,omitempty" description:"Maintains the order of parameters and fields in the OpenAPI specification"`
-	DeduplicateErrors           bool               `yaml:"deduplicateErrors,omitempty" description:"Deduplicates errors that have the same schema"`
-	UsageSnippets               *UsageSnippets     `yaml:"usageSnippets,omitempty"`
-	UseClassNamesForArrayFields bool               `yaml:"useClassNamesForArrayFields,omitempty" description:"Use class names for array fields instead of the child's schema type"`
-	Fixes                       *Fixes             `yaml:"fixes,omitempty"`
+	DevContainers               *DevContainers     `yaml:"devContainers,omitempty" impact:"none"`
+	BaseServerURL               string             `yaml:"baseServerUrl,omitempty" legacyName:"baseServerURL" description:"The base URL of the server. This value will be used if global servers are not defined in the spec." default:"" validationRegex:"^(https?):\\/\\/([\\w\\-]+\\.)+\\w+(\\/.*)?$" validationMessage:"Must be a valid server URL"`
+	SDKClassName                string             `yaml:"sdkClassName,omitempty" description:"Generated name of the root SDK class" default:"SDK" validationRegex:"^[\\w.\\-]+$" validationMessage:"Letters, numbers, or .-_ only" impact:"major"`
+	MaintainOpenAPIOrder        bool               `yaml:"maintainOpenAPIOrder,omitempty" description:"Maintains the order of things like parameters and fields when generating the SDK" default:"false" newSDKDefault:"true" impact:"major"`
+	DeduplicateErrors           bool               `yaml:"deduplicateErrors,omitempty" description:"Deduplicates errors that have the same schema" default:"false" impact:"major"`
+	UsageSnippets               *UsageSnippets     `yaml:"usageSnippets,omitempty" impact:"none"`
+	UseClassNamesForArrayFields bool               `yaml:"useClassNamesForArrayFields,omitempty" description:"Use class names for array fields instead of the child's schema type" default:"false" newSDKDefault:"true" impact:"major"`
+	Fixes                       *Fixes             `yaml:"fixes,omitempty" impact:"major"`
+	FixesBaseline               string             `yaml:"fixesBaseline,omitempty" description:"Enables every fix introduced up to and including this month, in the form YYYY-MM" default:"" validationRegex:"^\\d{4}-(0[1-9]|1[0-2])$" validationMessage:"Must be a year and month such as 2025-04" impact:"major"`
+	FixesOptOut                 []string           `yaml:"fixesOptOut,omitempty" description:"Fixes left disabled when enabling the fixes of fixesBaseline" impact:"major"`
 	Auth                        *Auth              `yaml:"auth,omitempty"`
-	SkipErrorSuffix             bool               `yaml:"skipErrorSuffix,omitempty" description:"Skips the automatic addition of an error suffix to error types"`
-	InferSSEOverload            bool               `yaml:"inferSSEOverload,omitempty" description:"Generates an overload if generator detects that the request body field 'stream: true' is used for client intent to request 'text/event-stream' response"`
-	SDKHooksConfigAccess        bool               `yaml:"sdkHooksConfigAccess,omitempty" description:"Enables access to the SDK configuration from hooks"`
-	Schemas                     Schemas            `yaml:"schemas"`
-	RequestBodyFieldName        string             `yaml:"requestBodyFieldName" description:"The name of the field to use for the request body in generated SDKs"`
-	VersioningStrategy          VersioningStrategy `yaml:"versioningStrategy,omitempty" enum:"automatic,manual" description:"Controls how SDK versions are determined. 'automatic' (default) bumps versions based on changes, 'manual' uses the version in gen.yaml as-is."`
+	SkipErrorSuffix             bool               `yaml:"skipErrorSuffix,omitempty" description:"Skips the automatic addition of an error suffix to error types" default:"false" impact:"major"`
+	InferSSEOverload            bool               "yaml:\"inferSSEOverload,omitempty\" description:\"Generates an overload if generator detects that the request body field `stream: true` is used for client intent to request `text/event-stream` response\" default:\"false\" newSDKDefault:\"true\""
+	SDKHooksConfigAccess        bool               `yaml:"sdkHooksConfigAccess,omitempty" description:"Enables access to the SDK configuration from hooks" default:"false" newSDKDefault:"true"`
+	Schemas                     Schemas            `yaml:"schemas" impact:"major"`
+	RequestBodyFieldName        string             `yaml:"requestBodyFieldName" description:"The name of the field to use for the request body in generated SDKs" default:"" newSDKDefault:"body" impact:"major"`
+	VersioningStrategy          VersioningStrategy `yaml:"versioningStrategy,omitempty" enum:"automatic,manual" description:"Controls how SDK versions are determined. 'automatic' (default) bumps versions based on changes, 'manual' uses the version in gen.yaml as-is." default:"automatic" impact:"none"`
 
 	// Mock server generation configuration.
-	MockServer *MockServer `yaml:"mockServer,omitempty"`
+	MockServer *MockServer `yaml:"mockServer,omitempty" impact:"none"`
 
 	// PersistentEdits configures whether user edits persist across regenerations
-	PersistentEdits PersistentEdits `yaml:"persistentEdits"`
-	Tests           Tests           `yaml:"tests,omitempty"`
+	PersistentEdits PersistentEdits `yaml:"persistentEdits" impact:"none"`
+	Tests           Tests           `yaml:"tests,omitempty" impact:"none"`
 
 	AdditionalProperties map[string]any `yaml:",inline" jsonschema:"-"` // Captures any additional properties that are not explicitly defined for backwards/forwards compatibility
 }
@@ -231,22 +237,25 @@ type LanguageConfig struct {
 }
 
 type SDKGenConfigField struct {
-	Name                  string  `yaml:"name" json:"name"`
-	Required              bool    `yaml:"required" json:"required"`
-	RequiredForPublishing *bool   `yaml:"requiredForPublishing,omitempty" json:"required_for_publishing,omitempty"`
-	DefaultValue          *any    `yaml:"defaultValue,omitempty" json:"default_value,omitempty"`
-	Description           *string `yaml:"description,omitempty" json:"description,omitempty"`
-	Language              *string `yaml:"language,omitempty" json:"language,omitempty"`
-	SecretName            *string `yaml:"secretName,omitempty" json:"secret_name,omitempty"`
-	ValidationRegex       *string `yaml:"validationRegex,omitempty" json:"validation_regex,omitempty"`
-	ValidationMessage     *string `yaml:"validationMessage,omitempty" json:"validation_message,omitempty"`
-	TestValue             *any    `yaml:"testValue,omitempty" json:"test_value,omitempty"`
+	Name                  string       `yaml:"name" json:"name"`
+	Required              bool         `yaml:"required" json:"required"`
+	RequiredForPublishing *bool        `yaml:"requiredForPublishing,omitempty" json:"required_for_publishing,omitempty"`
+	DefaultValue          *any         `yaml:"defaultValue,omitempty" json:"default_value,omitempty"`
+	Description           *string      `yaml:"description,omitempty" json:"description,omitempty"`
+	Language              *string      `yaml:"language,omitempty" json:"language,omitempty"`
+	SecretName            *string      `yaml:"secretName,omitempty" json:"secret_name,omitempty"`
+	ValidationRegex       *string      `yaml:"validationRegex,omitempty" json:"validation_regex,omitempty"`
+	ValidationMessage     *string      `yaml:"validationMessage,omitempty" json:"validation_message,omitempty"`
+	TestValue             *any         `yaml:"testValue,omitempty" json:"test_value,omitempty"`
+	Deprecation           *Deprecation `yaml:"deprecation,omitempty" json:"deprecation,omitempty"`
+	Impact                Impact       `yaml:"impact,omitempty" json:"impact,omitempty"`
 }
 
 // Ensure you update schema/gen.config.schema.json on changes
 type Configuration struct {
 	_             struct{}                  `title:"Gen YAML Configuration Schema" additionalProperties:"false"`
-	ConfigVersion string                    `yaml:"configVersion" description:"The version of the configuration file" minLength:"1" required:"true"`
+	ConfigVersion string                    `yaml:"configVersion" description:"The version of the configuration file" minLength:"1" required:"true" impact:"none"`
+	Extends       []string                  `yaml:"extends,omitempty" description:"Paths to shared base configs, relative to this file. Later entries take precedence and values in this file take precedence over all of them" impact:"none"`
 	Generation    Generation                `yaml:"generation" required:"true"`
 	Languages     map[string]LanguageConfig `yaml:",inline" jsonschema:"-"`
 	New           map[string]bool           `yaml:"-"`
@@ -397,43 +406,16 @@ type Target struct {
 func GetDefaultConfig(newSDK bool, getLangDefaultFunc GetLanguageDefaultFunc, langs map[string]bool) (*Configuration, error) {
 	defaults := GetGenerationDefaults(newSDK)
 
-	fields := map[string]any{}
+	var genConfig Generation
 	for _, field := range defaults {
-		if field.DefaultValue != nil {
-			if strings.Contains(field.Name, ".") {
-				parts := strings.Split(field.Name, ".")
-
-				currMap := fields
-
-				for i, part := range parts {
-					if i == len(parts)-1 {
-						currMap[part] = *field.DefaultValue
-					} else {
-						if _, ok := currMap[part]; !ok {
-							currMap[part] = map[string]any{}
-						}
-
-						currMap = currMap[part].(map[string]any)
-					}
-				}
-			} else {
-				fields[field.Name] = *field.DefaultValue
-			}
+		if field.DefaultValue == nil {
+			continue
 		}
-	}
-
-	var genConfig Generation
 
-	d, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
-		Result:  &genConfig,
-		TagName: "yaml",
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	if err := d.Decode(fields); err != nil {
-		return nil, err
+		// Defaults for fields that no longer exist in Generation are ignored
+		if err := setField(reflect.ValueOf(&genConfig).Elem(), strings.Split(field.Name, "."), field.Name, *field.DefaultValue, ""); err != nil && !errors.Is(err, ErrUnknownPath) {
+			return nil, err
+		}
 	}
 
 	cfg := &Configuration{
@@ -462,194 +444,140 @@ func GetDefaultConfig(newSDK bool, getLangDefaultFunc GetLanguageDefaultFunc, la
 	return cfg, nil
 }
 
+// untypedGenerationFields declares the tags of generation keys that have no field on Generation and are only captured
+// in AdditionalProperties.
+var untypedGenerationFields = map[string]reflect.StructField{
+	"fixes.methodSignaturesApr2024": {
+		Name: "MethodSignaturesApr2024",
+		Type: reflect.TypeOf(false),
+		Tag:  `description:"Enables fixes that will detect and mark optional request and security method arguments and order them according to optionality." default:"false" newSDKDefault:"true"`,
+	},
+}
+
+// GetGenerationDefaults returns the generation fields that have a default, or are tagged noDefault, derived from the tags
+// on Generation and its nested structs in the order they are declared. The default tag holds the value for existing SDKs and newSDKDefault
+// overrides it for new SDKs.
 func GetGenerationDefaults(newSDK bool) []SDKGenConfigField {
-	return []SDKGenConfigField{
-		{
-			Name:              "baseServerURL",
-			Required:          false,
-			DefaultValue:      ptr(""),
-			Description:       pointer.From("The base URL of the server. This value will be used if global servers are not defined in the spec."),
-			ValidationRegex:   pointer.From(`^(https?):\/\/([\w\-]+\.)+\w+(\/.*)?$`),
-			ValidationMessage: pointer.From("Must be a valid server URL"),
-		},
-		{
-			Name:              "sdkClassName",
-			Required:          false,
-			DefaultValue:      ptr("SDK"),
-			Description:       pointer.From("Generated name of the root SDK class"),
-			ValidationRegex:   pointer.From(`^[\w.\-]+$`),
-			ValidationMessage: pointer.From("Letters, numbers, or .-_ only"),
-		},
-		{
-			Name:         "maintainOpenAPIOrder",
-			Required:     false,
-			DefaultValue: ptr(newSDK),
-			Description:  pointer.From("Maintains the order of things like parameters and fields when generating the SDK"),
-		},
-		{
-			Name:         "deduplicateErrors",
-			Required:     false,
-			DefaultValue: ptr(false),
-			Description:  pointer.From("Deduplicates errors that have the same schema"),
-		},
-		{
-			Name:         "inferSSEOverload",
-			Required:     false,
-			DefaultValue: ptr(newSDK),
-			Description:  pointer.From("Generates an overload if generator detects that the request body field `stream: true` is used for client intent to request `text/event-stream` response"),
-		},
-		{
-			Name:         "skipErrorSuffix",
-			Required:     false,
-			DefaultValue: ptr(false),
-			Description:  pointer.From("Skips the automatic addition of an error suffix to error types"),
-		},
-		{
-			Name:         "usageSnippets.optionalPropertyRendering",
-			Required:     false,
-			DefaultValue: ptr(OptionalPropertyRenderingOptionWithExample),
-			Description:  pointer.From("Controls how optional properties are rendered in usage snippets, by default they will be rendered when an example is present in the OpenAPI spec"),
-		},
-		{
-			Name:         "usageSnippets.sdkInitStyle",
-			Required:     false,
-			DefaultValue: ptr(SDKInitStyleConstructor),
-			Description:  pointer.From("Controls how the SDK initialization is depicted in usage snippets, by default it will use the constructor"),
-		},
-		{
-			Name:         "usageSnippets.serverToShowInSnippets",
-			Required:     false,
-			DefaultValue: nil,
-			Description:  pointer.From("Controls which server is shown in usage snippets. If unset, no server will be shown. If an integer, it will be used as the server index. Otherwise, it will look for a matching server ID."),
-		},
-		{
-			Name:         "useClassNamesForArrayFields",
-			Required:     false,
-			DefaultValue: ptr(newSDK),
-			Description:  pointer.From("Use class names for array fields instead of the child's schema type"),
-		},
-		{
-			Name:         "fixes.nameResolutionDec2023",
-			Required:     false,
-			DefaultValue: ptr(newSDK),
-			Description:  pointer.From("Enables a number of breaking changes introduced in December 2023, that improve name resolution for inline schemas and reduce chances of name collisions"),
-		},
-		{
-			Name:         "fixes.nameResolutionFeb2025",
-			Required:     false,
-			DefaultValue: ptr(newSDK),
-			Description:  pointer.From("Enables a number of breaking changes introduced in February 2025, that improve name resolution for inline schemas and reduce chances of name collisions"),
-		},
-		{
-			Name:         "fixes.parameterOrderingFeb2024",
-			Required:     false,
-			DefaultValue: ptr(newSDK),
-			Description:  pointer.From("Enables fixes to the ordering of parameters for an operation if they include multiple types of parameters (ie header, query, path) to match the order they are defined in the OpenAPI spec"),
-		},
-		{
-			Name:         "fixes.requestResponseComponentNamesFeb2024",
-			Required:     false,
-			DefaultValue: ptr(newSDK),
-			Description:  pointer.From("Enables fixes that will name inline schemas within request and response components with the component name of the parent if only one content type is defined"),
-		},
-		{
-			Name:         "fixes.methodSignaturesApr2024",
-			Required:     false,
-			DefaultValue: ptr(newSDK),
-			Description:  pointer.From("Enables fixes that will detect and mark optional request and security method arguments and order them according to optionality."),
-		},
-		{
-			Name:         "auth.oAuth2ClientCredentialsEnabled",
-			Required:     false,
-			DefaultValue: ptr(newSDK),
-			Description:  pointer.From("Enables support for OAuth2 client credentials grant type (Enterprise tier only)"),
-		},
-		{
-			Name:         "auth.oAuth2PasswordEnabled",
-			Required:     false,
-			DefaultValue: ptr(newSDK),
-			Description:  pointer.From("Enables support for OAuth2 resource owner password credentials grant type (Enterprise tier only)"),
-		},
-		{
-			Name:         "tests.generateTests",
-			Required:     false,
-			DefaultValue: ptr(!newSDK),
-			Description:  pointer.From("Enables generation of tests"),
-		},
-		{
-			Name:         "tests.generateNewTests",
-			Required:     false,
-			DefaultValue: ptr(newSDK),
-			Description:  pointer.From("Enables generation of new tests for any new operations found in the OpenAPI spec"),
-		},
-		{
-			Name:         "tests.skipResponseBodyAssertions",
-			Required:     false,
-			DefaultValue: ptr(false),
-			Description:  pointer.From("Skips the generation of response body assertions in tests"),
-		},
-		{
-			Name:         "fixes.securityFeb2025",
-			Required:     false,
-			DefaultValue: ptr(newSDK),
-			Description:  pointer.From("Enables fixes and refactoring for security that were introduced in February 2025"),
-		},
-		{
-			Name:         "fixes.sharedErrorComponentsApr2025",
-			Required:     false,
-			DefaultValue: ptr(newSDK),
-			Description:  pointer.From("Enables fixes that mean that when a component is used in both 2XX and 4XX responses, only the top level component will be duplicated to the errors scope as opposed to the entire component tree"),
-		},
-		{
-			Name:         "sdkHooksConfigAccess",
-			Required:     false,
-			DefaultValue: ptr(newSDK),
-			Description:  pointer.From("Enables access to the SDK configuration from hooks"),
-		},
-		{
-			Name:         "auth.hoistGlobalSecurity",
-			Required:     false,
-			DefaultValue: ptr(true),
-			Description:  pointer.From("Enables hoisting of operation-level security schemes to global level when no global security is defined"),
-		},
-		{
-			Name:         "schemas.allOfMergeStrategy",
-			Required:     false,
-			DefaultValue: ptr(AllOfMergeStrategyShallowMerge),
-			Description:  pointer.From("Controls how allOf schemas are merged, by default they will be merged using a shallow merge"),
-		},
-		{
-			Name:     "requestBodyFieldName",
-			Required: false,
-			DefaultValue: func() *any {
-				if newSDK {
-					return ptr("body")
-				}
-				return ptr("")
-			}(),
-			Description: pointer.From("The name of the field to use for the request body in generated SDKs"),
-		},
-		{
-			Name:         "fixes.sharedNestedComponentsJan2026",
-			Required:     false,
-			DefaultValue: ptr(newSDK),
-			Description:  pointer.From("Fixes component naming when the same schema is referenced in multiple places within nested structures, ensuring consistent naming based on the original component definition"),
-		},
-		{
-			Name:         "fixes.nameOverrideFeb2026",
-			Required:     false,
-			DefaultValue: ptr(newSDK),
-			Description:  pointer.From("Prevents component-level x-speakeasy-name-override from affecting parent names when referencing schema via $ref or hoisting allOf extensions"),
-		},
-		{
-			Name:         "versioningStrategy",
-			Required:     false,
-			DefaultValue: ptr(VersioningStrategyAutomatic),
-			Description:  pointer.From("Controls how SDK versions are determined. 'automatic' (default) bumps versions based on changes, 'manual' uses the version in gen.yaml as-is."),
-		},
+	fields, err := generationDefaults(newSDK)
+	if err != nil {
+		// The tags are checked at init, so this can't happen
+		panic(err)
+	}
+	return fields
+}
+
+func init() {
+	for _, newSDK := range []bool{false, true} {
+		if _, err := generationDefaults(newSDK); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// generationDefaults returns the fields listed by GetGenerationDefaults, along with an error for any default tag that
+// can't be converted to the type of its field.
+func generationDefaults(newSDK bool) ([]SDKGenConfigField, error) {
+	var fields []SDKGenConfigField
+	var errs []error
+	add := func(name string, f reflect.StructField) {
+		field, ok, err := generationField(name, f, newSDK)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if ok {
+			fields = append(fields, field)
+		}
+	}
+
+	walkGenerationFields(reflect.TypeOf(Generation{}), "", add)
+	for _, name := range slices.Sorted(maps.Keys(untypedGenerationFields)) {
+		add(name, untypedGenerationFields[name])
+	}
+
+	return fields, errors.Join(errs...)
+}
+
+// walkGenerationFields calls fn with the dotted name of every field beneath t, descending into nested structs.
+func walkGenerationFields(t reflect.Type, prefix string, fn func(name string, f reflect.StructField)) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, inline := yamlFieldName(f)
+		if name == "" || inline {
+			continue
+		}
+
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if _, ok := f.Tag.Lookup("default"); !ok && ft.Kind() == reflect.Struct {
+			walkGenerationFields(ft, prefix+name+".", fn)
+			continue
+		}
+
+		fn(prefix+name, f)
 	}
 }
 
+// generationField returns the SDKGenConfigField declared by the tags of f, and whether it is listed by
+// GetGenerationDefaults, which requires a default or noDefault tag.
+func generationField(name string, f reflect.StructField, newSDK bool) (SDKGenConfigField, bool, error) {
+	// Fields listed before their yaml key was settled keep the name they were listed under
+	if legacyName, ok := f.Tag.Lookup("legacyName"); ok {
+		name = name[:strings.LastIndexByte(name, '.')+1] + legacyName
+	}
+
+	field := SDKGenConfigField{
+		Name:     name,
+		Required: false,
+	}
+	if description, ok := f.Tag.Lookup("description"); ok {
+		field.Description = pointer.From(description)
+	}
+	if regex, ok := f.Tag.Lookup("validationRegex"); ok {
+		field.ValidationRegex = pointer.From(regex)
+	}
+	if message, ok := f.Tag.Lookup("validationMessage"); ok {
+		field.ValidationMessage = pointer.From(message)
+	}
+
+	defaultTag, ok := f.Tag.Lookup("default")
+	if !ok {
+		// Fields that are unset by default can still be listed with noDefault
+		return field, f.Tag.Get("noDefault") == "true", nil
+	}
+	if newSDKTag, ok := f.Tag.Lookup("newSDKDefault"); ok && newSDK {
+		defaultTag = newSDKTag
+	}
+
+	value, err := parseDefaultTag(f, defaultTag)
+	if err != nil {
+		return field, true, fmt.Errorf("invalid default for %s: %w", name, err)
+	}
+	field.DefaultValue = ptr(value)
+
+	return field, true, nil
+}
+
+// parseDefaultTag converts the value of a default tag to the type of the field it is declared on.
+func parseDefaultTag(f reflect.StructField, value string) (any, error) {
+	t := f.Type
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	v, err := convertValue(reflect.ValueOf(value), t)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.Interface(), nil
+}
+
 func (c *Configuration) GetGenerationFieldsMap() (map[string]any, error) {
 	fields := map[string]any{}
 
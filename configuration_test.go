@@ -4,11 +4,16 @@ package config_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
+	config "github.com/speakeasy-api/sdk-gen-config"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -38,3 +43,39 @@ func TestConfigSchemaInSync(t *testing.T) {
 			"Run: cd tools/schema-gen && go run . -type config -out ../../schemas/gen.config.schema.json\n"+
 			"Then commit the updated file.")
 }
+
+func TestGetGenerationDefaults(t *testing.T) {
+	// The fields and their order are part of the public output, so changes must be deliberate
+	want, err := os.ReadFile(filepath.Join("testdata", "generation-defaults.json"))
+	require.NoError(t, err)
+
+	got := map[string][]config.SDKGenConfigField{
+		"existing": config.GetGenerationDefaults(false),
+		"new":      config.GetGenerationDefaults(true),
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	require.NoError(t, enc.Encode(got))
+	assert.JSONEq(t, string(want), buf.String())
+
+	defaults := map[string]any{}
+	for _, field := range got["existing"] {
+		if field.DefaultValue != nil {
+			defaults[field.Name] = *field.DefaultValue
+		}
+	}
+	assert.Equal(t, config.AllOfMergeStrategyShallowMerge, defaults["schemas.allOfMergeStrategy"])
+
+	// Every fix declares a default, so new fixes can't be added without one
+	fixes := reflect.TypeOf(config.Fixes{})
+	for i := 0; i < fixes.NumField(); i++ {
+		f := fixes.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		assert.Contains(t, defaults, "fixes."+name)
+	}
+}
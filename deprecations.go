@@ -0,0 +1,313 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/speakeasy-api/openapi/pointer"
+)
+
+// Deprecation describes a gen.yaml key that is deprecated or has been removed.
+type Deprecation struct {
+	Since      string `yaml:"since,omitempty" json:"since,omitempty"`
+	RemovedIn  string `yaml:"removedIn,omitempty" json:"removed_in,omitempty"`
+	ReplacedBy string `yaml:"replacedBy,omitempty" json:"replaced_by,omitempty"` // Dotted path of the key that replaces this one, for example "generation.sdkClassName"
+	Message    string `yaml:"message,omitempty" json:"message,omitempty"`
+}
+
+type WarningKind string
+
+const (
+	// WarningDeprecatedKey is a key that is deprecated or no longer supported
+	WarningDeprecatedKey WarningKind = "deprecatedKey"
+	// WarningUnknownKey is a key that isn't recognized and is only kept for forwards compatibility
+	WarningUnknownKey WarningKind = "unknownKey"
+)
+
+// Warning describes a problem with gen.yaml that doesn't prevent it from being loaded.
+type Warning struct {
+	Kind        WarningKind
+	Path        string
+	Source      ValueSource
+	Message     string
+	Deprecation *Deprecation
+	Rewritten   bool // The key was rewritten to its replacement, see WithRewriteDeprecated
+}
+
+func (w Warning) String() string {
+	if w.Source.File == "" {
+		return w.Message
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", w.Source.File, w.Source.Line, w.Source.Column, w.Message)
+}
+
+// removedGenerationKeys holds generation keys that are no longer read, so their deprecations can be declared on struct
+// tags like those of the fields of Generation. Values for them are only captured in Generation.AdditionalProperties.
+type removedGenerationKeys struct {
+	Comments               any `yaml:"comments" description:"Comment generation options" deprecatedSince:"1.0.0" removedIn:"2.0.0"`
+	SingleTagPerOp         any `yaml:"singleTagPerOp" description:"Only uses the first tag of an operation for namespacing" deprecatedSince:"1.0.0" removedIn:"2.0.0"`
+	TagNamespacingDisabled any `yaml:"tagNamespacingDisabled" description:"Disables namespacing of operations by tag" deprecatedSince:"1.0.0" removedIn:"2.0.0"`
+	BaseServerURL          any `yaml:"baseServerURL" description:"The base URL of the server" replacedBy:"generation.baseServerUrl" deprecationMessage:"keys are case-sensitive, so this one is ignored"`
+}
+
+// deprecatedGenerationFields returns the fields of Generation, its nested structs and removedGenerationKeys that declare
+// a deprecation with the deprecatedSince, removedIn, replacedBy or deprecationMessage tags.
+var deprecatedGenerationFields = sync.OnceValue(func() []SDKGenConfigField {
+	var fields []SDKGenConfigField
+	var walk func(t reflect.Type, prefix string)
+	walk = func(t reflect.Type, prefix string) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, inline := yamlFieldName(f)
+			if !f.IsExported() || name == "" || inline {
+				continue
+			}
+
+			if deprecation := deprecationTag(f); deprecation != nil {
+				field := SDKGenConfigField{Name: prefix + name, Deprecation: deprecation}
+				if description, ok := f.Tag.Lookup("description"); ok {
+					field.Description = pointer.From(description)
+				}
+				fields = append(fields, field)
+				continue
+			}
+
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				walk(ft, prefix+name+".")
+			}
+		}
+	}
+	walk(reflect.TypeOf(Generation{}), "")
+	walk(reflect.TypeOf(removedGenerationKeys{}), "")
+
+	return fields
+})
+
+// deprecationTag returns the deprecation declared by the tags of f, or nil if it isn't deprecated.
+func deprecationTag(f reflect.StructField) *Deprecation {
+	d := &Deprecation{
+		Since:      f.Tag.Get("deprecatedSince"),
+		RemovedIn:  f.Tag.Get("removedIn"),
+		ReplacedBy: f.Tag.Get("replacedBy"),
+		Message:    f.Tag.Get("deprecationMessage"),
+	}
+	if *d == (Deprecation{}) {
+		return nil
+	}
+	return d
+}
+
+// GetDeprecatedGenerationFields returns the generation fields that are deprecated or have been removed.
+func GetDeprecatedGenerationFields() []SDKGenConfigField {
+	return slices.Clone(deprecatedGenerationFields())
+}
+
+// WithDeprecatedFields provides additional fields, such as those for a language, whose Deprecation is reported
+// in Config.Warnings when they are set. Fields without a Deprecation are ignored.
+func WithDeprecatedFields(fields ...SDKGenConfigField) Option {
+	return func(o *options) {
+		o.deprecatedFields = append(o.deprecatedFields, fields...)
+	}
+}
+
+// WithRewriteDeprecated moves the values of deprecated keys that have a replacement to the replacement key in gen.yaml.
+// A value already set for the replacement key takes precedence over the deprecated one.
+func WithRewriteDeprecated() Option {
+	return func(o *options) {
+		o.rewriteDeprecated = true
+	}
+}
+
+type deprecatedField struct {
+	path        string
+	deprecation *Deprecation
+}
+
+func (o *options) deprecations() []deprecatedField {
+	var fields []deprecatedField
+	for _, field := range append(GetDeprecatedGenerationFields(), o.deprecatedFields...) {
+		if field.Deprecation == nil {
+			continue
+		}
+
+		path := "generation." + field.Name
+		if field.Language != nil {
+			path = *field.Language + "." + field.Name
+		}
+
+		fields = append(fields, deprecatedField{path: path, deprecation: field.Deprecation})
+	}
+	return fields
+}
+
+func (d *Deprecation) describe(path string) string {
+	var sb strings.Builder
+	sb.WriteString(path + " is deprecated")
+	if d.Since != "" {
+		sb.WriteString(" since " + d.Since)
+	}
+	if d.RemovedIn != "" {
+		sb.WriteString(" and is removed in " + d.RemovedIn)
+	}
+	if d.ReplacedBy != "" {
+		sb.WriteString(", use " + d.ReplacedBy + " instead")
+	}
+	if d.Message != "" {
+		sb.WriteString(": " + d.Message)
+	}
+	return sb.String()
+}
+
+// rewriteDeprecated moves the values of deprecated keys in cfg to their replacements and returns the paths it rewrote.
+func rewriteDeprecated(cfg map[string]any, fields []deprecatedField) []string {
+	var rewritten []string
+	for _, field := range fields {
+		if field.deprecation.ReplacedBy == "" {
+			continue
+		}
+
+		value, ok := deletePath(cfg, field.path)
+		if !ok {
+			continue
+		}
+
+		if !hasPath(cfg, field.deprecation.ReplacedBy) {
+			setPath(cfg, field.deprecation.ReplacedBy, value)
+		}
+		rewritten = append(rewritten, field.path)
+	}
+	return rewritten
+}
+
+// collectWarnings reports the deprecated keys set in any of the layers and the unknown keys in the resolved config.
+func collectWarnings(layers []*configLayer, fields []deprecatedField, rewritten []string, cfg *Configuration) []Warning {
+	var warnings []Warning
+	seen := map[string]bool{}
+
+	for _, field := range fields {
+		for _, layer := range layers {
+			if !hasPath(layer.values, field.path) || seen[field.path+"@"+layer.path] {
+				continue
+			}
+			seen[field.path+"@"+layer.path] = true
+
+			warnings = append(warnings, Warning{
+				Kind:        WarningDeprecatedKey,
+				Path:        field.path,
+				Source:      layer.positions[field.path],
+				Message:     field.deprecation.describe(field.path),
+				Deprecation: field.deprecation,
+				Rewritten:   slices.Contains(rewritten, field.path),
+			})
+		}
+	}
+
+	unknown := map[string]any{}
+	collectAdditionalProperties(reflect.ValueOf(cfg.Generation), "generation.", unknown)
+	for name := range untypedGenerationFields {
+		delete(unknown, "generation."+name)
+	}
+
+	for _, path := range sortedKeys(unknown) {
+		if slices.ContainsFunc(fields, func(f deprecatedField) bool { return f.path == path }) {
+			continue
+		}
+
+		w := Warning{
+			Kind:    WarningUnknownKey,
+			Path:    path,
+			Message: path + " is not a recognized key",
+		}
+		for _, layer := range layers {
+			if source, ok := layer.positions[path]; ok {
+				w.Source = source
+			}
+		}
+		warnings = append(warnings, w)
+	}
+
+	return warnings
+}
+
+// collectAdditionalProperties adds the keys captured in the AdditionalProperties of v and the structs nested in it to
+// unknown, under their dotted paths.
+func collectAdditionalProperties(v reflect.Value, prefix string, unknown map[string]any) {
+	v = indirectValue(v)
+	if !v.IsValid() || v.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, inline := yamlFieldName(f)
+		if inline {
+			if props, ok := v.Field(i).Interface().(map[string]any); ok {
+				for k, value := range props {
+					unknown[prefix+k] = value
+				}
+			}
+			continue
+		}
+
+		if name != "" {
+			collectAdditionalProperties(v.Field(i), prefix+name+".", unknown)
+		}
+	}
+}
+
+// parentMap returns the map holding the key at path in m, if there is one.
+func parentMap(m map[string]any, path string) (map[string]any, bool) {
+	if !strings.Contains(path, ".") {
+		return m, true
+	}
+	parent, ok := lookupPath(m, parentKey(path)).(map[string]any)
+	return parent, ok
+}
+
+func hasPath(m map[string]any, path string) bool {
+	parent, ok := parentMap(m, path)
+	if !ok {
+		return false
+	}
+	_, ok = parent[leafKey(path)]
+	return ok
+}
+
+func deletePath(m map[string]any, path string) (any, bool) {
+	parent, ok := parentMap(m, path)
+	if !ok {
+		return nil, false
+	}
+
+	value, ok := parent[leafKey(path)]
+	if !ok {
+		return nil, false
+	}
+	delete(parent, leafKey(path))
+	return value, true
+}
+
+func setPath(m map[string]any, path string, value any) {
+	parts := strings.Split(path, ".")
+	current := m
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := current[part].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			current[part] = next
+		}
+		current = next
+	}
+	current[parts[len(parts)-1]] = value
+}
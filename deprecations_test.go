@@ -0,0 +1,203 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/speakeasy-api/openapi/pointer"
+	"github.com/speakeasy-api/sdk-gen-config/lockfile"
+	"github.com/speakeasy-api/sdk-gen-config/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Warnings(t *testing.T) {
+	getUUID = func() string {
+		return "123"
+	}
+	lockfile.GetUUID = getUUID
+
+	genYaml := `configVersion: 2.0.0
+generation:
+  sdkClassName: speakeasy
+  singleTagPerOp: true
+  unknownOption: true
+go:
+  version: 1.0.0
+  oldPackageName: openapi
+`
+
+	deprecatedFields := []SDKGenConfigField{
+		{
+			Name:        "oldPackageName",
+			Language:    pointer.From("go"),
+			Deprecation: &Deprecation{Since: "1.2.0", RemovedIn: "2.0.0", ReplacedBy: "go.packageName"},
+		},
+		{
+			Name:     "packageName",
+			Language: pointer.From("go"),
+		},
+	}
+
+	tests := []struct {
+		name          string
+		opts          []Option
+		wantRewritten bool
+		wantGenYaml   string
+	}{
+		{
+			name:        "reports deprecated and unknown keys",
+			opts:        []Option{WithDeprecatedFields(deprecatedFields...)},
+			wantGenYaml: genYaml,
+		},
+		{
+			name:          "rewrites deprecated keys with a replacement",
+			opts:          []Option{WithDeprecatedFields(deprecatedFields...), WithRewriteDeprecated()},
+			wantRewritten: true,
+			wantGenYaml: `configVersion: 2.0.0
+generation:
+  sdkClassName: speakeasy
+  singleTagPerOp: true
+  unknownOption: true
+go:
+  version: 1.0.0
+  packageName: openapi
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			speakeasyDir := filepath.Join(dir, ".speakeasy")
+			genYamlPath := filepath.Join(speakeasyDir, "gen.yaml")
+			testutils.CreateTempFile(t, speakeasyDir, "gen.yaml", genYaml)
+			testutils.CreateTempFile(t, speakeasyDir, "gen.lock", testutils.ReadTestFile(t, "v200-gen.lock"))
+
+			cfg, err := Load(dir, append(tt.opts, WithLanguages("go"))...)
+			require.NoError(t, err)
+
+			assert.Equal(t, []Warning{
+				{
+					Kind:        WarningDeprecatedKey,
+					Path:        "generation.singleTagPerOp",
+					Source:      ValueSource{Layer: SourceFile, File: genYamlPath, Line: 4, Column: 3},
+					Message:     "generation.singleTagPerOp is deprecated since 1.0.0 and is removed in 2.0.0",
+					Deprecation: &Deprecation{Since: v1, RemovedIn: v2},
+				},
+				{
+					Kind:        WarningDeprecatedKey,
+					Path:        "go.oldPackageName",
+					Source:      ValueSource{Layer: SourceFile, File: genYamlPath, Line: 8, Column: 3},
+					Message:     "go.oldPackageName is deprecated since 1.2.0 and is removed in 2.0.0, use go.packageName instead",
+					Deprecation: deprecatedFields[0].Deprecation,
+					Rewritten:   tt.wantRewritten,
+				},
+				{
+					Kind:    WarningUnknownKey,
+					Path:    "generation.unknownOption",
+					Source:  ValueSource{Layer: SourceFile, File: genYamlPath, Line: 5, Column: 3},
+					Message: "generation.unknownOption is not a recognized key",
+				},
+			}, cfg.Warnings)
+
+			if tt.wantRewritten {
+				assert.Equal(t, "openapi", cfg.Config.Languages["go"].Cfg["packageName"])
+				assert.NotContains(t, cfg.Config.Languages["go"].Cfg, "oldPackageName")
+			}
+
+			data, err := os.ReadFile(genYamlPath)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantGenYaml, string(data))
+		})
+	}
+}
+
+func TestRewriteDeprecated_KeepsExistingReplacement(t *testing.T) {
+	cfg := map[string]any{
+		"go": map[string]any{
+			"oldPackageName": "old",
+			"packageName":    "new",
+		},
+	}
+
+	rewritten := rewriteDeprecated(cfg, []deprecatedField{
+		{path: "go.oldPackageName", deprecation: &Deprecation{ReplacedBy: "go.packageName"}},
+		{path: "go.missing", deprecation: &Deprecation{ReplacedBy: "go.other"}},
+	})
+
+	assert.Equal(t, []string{"go.oldPackageName"}, rewritten)
+	assert.Equal(t, map[string]any{
+		"go": map[string]any{
+			"packageName": "new",
+		},
+	}, cfg)
+}
+
+func TestGetDeprecatedGenerationFields(t *testing.T) {
+	deprecations := map[string]*Deprecation{}
+	for _, field := range GetDeprecatedGenerationFields() {
+		deprecations[field.Name] = field.Deprecation
+	}
+
+	assert.Equal(t, map[string]*Deprecation{
+		"comments":               {Since: v1, RemovedIn: v2},
+		"singleTagPerOp":         {Since: v1, RemovedIn: v2},
+		"tagNamespacingDisabled": {Since: v1, RemovedIn: v2},
+		"baseServerURL":          {ReplacedBy: "generation.baseServerUrl", Message: "keys are case-sensitive, so this one is ignored"},
+	}, deprecations)
+}
+
+func TestLoad_Warnings_NestedAndRewritten(t *testing.T) {
+	getUUID = func() string {
+		return "123"
+	}
+	lockfile.GetUUID = getUUID
+
+	dir := t.TempDir()
+	speakeasyDir := filepath.Join(dir, ".speakeasy")
+	genYamlPath := filepath.Join(speakeasyDir, "gen.yaml")
+	testutils.CreateTempFile(t, speakeasyDir, "gen.yaml", `configVersion: 2.0.0
+generation:
+  sdkClassName: speakeasy
+  baseServerURL: https://api.example.com
+  usageSnippets:
+    sdkInitStyl: builder
+  tests:
+    generateTest: true
+  persistentEdits:
+    enable: true
+  devContainers:
+    enabled: true
+    schemaPth: openapi.yaml
+  fixes:
+    methodSignaturesApr2024: true
+go:
+  version: 1.0.0
+`)
+	testutils.CreateTempFile(t, speakeasyDir, "gen.lock", testutils.ReadTestFile(t, "v200-gen.lock"))
+
+	cfg, err := Load(dir, WithLanguages("go"), WithRewriteDeprecated())
+	require.NoError(t, err)
+
+	var paths []string
+	for _, w := range cfg.Warnings {
+		paths = append(paths, string(w.Kind)+" "+w.Path)
+	}
+	assert.Equal(t, []string{
+		"deprecatedKey generation.baseServerURL",
+		"unknownKey generation.devContainers.schemaPth",
+		"unknownKey generation.persistentEdits.enable",
+		"unknownKey generation.tests.generateTest",
+		"unknownKey generation.usageSnippets.sdkInitStyl",
+	}, paths)
+	assert.True(t, cfg.Warnings[0].Rewritten)
+	assert.Equal(t, ValueSource{Layer: SourceFile, File: genYamlPath, Line: 5, Column: 5}, cfg.Warnings[4].Source)
+
+	// The value of the key with the wrong case is moved to the key that is read
+	assert.Equal(t, "https://api.example.com", cfg.Config.Generation.BaseServerURL)
+	data, err := os.ReadFile(genYamlPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "baseServerUrl: https://api.example.com\n")
+	assert.NotContains(t, string(data), "baseServerURL")
+}
@@ -0,0 +1,234 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Impact describes the effect a configuration change has on a generated SDK, in terms of the version bump it requires.
+type Impact string
+
+// Impacts in order of increasing severity
+const (
+	// ImpactNone doesn't affect the generated SDK
+	ImpactNone Impact = "none"
+	// ImpactPatch changes the generated SDK without changing its interface
+	ImpactPatch Impact = "patch"
+	// ImpactMinor changes the generated SDK in a backwards compatible way
+	ImpactMinor Impact = "minor"
+	// ImpactMajor changes the generated SDK in a breaking way and requires a major version bump
+	ImpactMajor Impact = "major"
+)
+
+var impactSeverity = map[Impact]int{
+	ImpactNone:  0,
+	ImpactPatch: 1,
+	ImpactMinor: 2,
+	ImpactMajor: 3,
+}
+
+// languageFieldImpacts are the impacts of common language options, other options are assumed to have an ImpactMinor.
+var languageFieldImpacts = map[string]Impact{
+	"version":     ImpactNone,
+	"packageName": ImpactMajor,
+}
+
+type ConfigChangeType string
+
+const (
+	ConfigChangeAdded   ConfigChangeType = "added"
+	ConfigChangeRemoved ConfigChangeType = "removed"
+	ConfigChangeChanged ConfigChangeType = "changed"
+)
+
+// ConfigChange is a single value that differs between two configurations.
+type ConfigChange struct {
+	Type   ConfigChangeType `json:"type"`
+	Path   string           `json:"path"`
+	Before any              `json:"before,omitempty"`
+	After  any              `json:"after,omitempty"`
+	Impact Impact           `json:"impact"`
+}
+
+func (c ConfigChange) String() string {
+	switch c.Type {
+	case ConfigChangeAdded:
+		return fmt.Sprintf("+ %s: %s [%s]", c.Path, formatChangeValue(c.After), c.Impact)
+	case ConfigChangeRemoved:
+		return fmt.Sprintf("- %s: %s [%s]", c.Path, formatChangeValue(c.Before), c.Impact)
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s [%s]", c.Path, formatChangeValue(c.Before), formatChangeValue(c.After), c.Impact)
+	}
+}
+
+// ConfigDiff is the set of changes between two configurations, ordered by path.
+type ConfigDiff struct {
+	Changes []ConfigChange `json:"changes"`
+	Impact  Impact         `json:"impact"` // The most severe impact of any change
+}
+
+// HasChanges returns true if the configurations differ.
+func (d *ConfigDiff) HasChanges() bool {
+	return len(d.Changes) > 0
+}
+
+// Breaking returns true if any change requires a major version bump.
+func (d *ConfigDiff) Breaking() bool {
+	return d.Impact == ImpactMajor
+}
+
+func (d *ConfigDiff) String() string {
+	if !d.HasChanges() {
+		return "No changes to gen.yaml\n"
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "gen.yaml changes (%s impact):\n", d.Impact)
+	for _, change := range d.Changes {
+		fmt.Fprintf(&sb, "  %s\n", change)
+	}
+	return sb.String()
+}
+
+// Diff compares two configurations and returns the values that were added, removed or changed, along with the impact
+// of each change. Impacts are taken from the fields provided, such as those for a language, then from the impact tags
+// of the Configuration structs, and default to ImpactMinor.
+func Diff(before, after *Configuration, fields ...SDKGenConfigField) (*ConfigDiff, error) {
+	beforeValues, err := flattenConfig(before)
+	if err != nil {
+		return nil, fmt.Errorf("could not flatten config: %w", err)
+	}
+	afterValues, err := flattenConfig(after)
+	if err != nil {
+		return nil, fmt.Errorf("could not flatten config: %w", err)
+	}
+
+	impacts := map[string]Impact{}
+	for _, field := range fields {
+		if field.Impact == "" {
+			continue
+		}
+		path := "generation." + field.Name
+		if field.Language != nil {
+			path = *field.Language + "." + field.Name
+		}
+		impacts[path] = field.Impact
+	}
+
+	paths := map[string]struct{}{}
+	for p := range beforeValues {
+		paths[p] = struct{}{}
+	}
+	for p := range afterValues {
+		paths[p] = struct{}{}
+	}
+
+	diff := &ConfigDiff{
+		Changes: []ConfigChange{},
+		Impact:  ImpactNone,
+	}
+
+	for _, p := range sortedKeys(paths) {
+		prev, prevOK := beforeValues[p]
+		next, nextOK := afterValues[p]
+
+		// Zero values are left out when marshaling, so fall back to the typed value for known fields
+		if !prevOK {
+			prev, prevOK = knownValue(before, p)
+		}
+		if !nextOK {
+			next, nextOK = knownValue(after, p)
+		}
+
+		if prevOK == nextOK && reflect.DeepEqual(prev, next) {
+			continue
+		}
+
+		change := ConfigChange{
+			Type:   ConfigChangeChanged,
+			Path:   p,
+			Before: prev,
+			After:  next,
+			Impact: fieldImpact(p, impacts),
+		}
+		switch {
+		case !prevOK:
+			change.Type = ConfigChangeAdded
+		case !nextOK:
+			change.Type = ConfigChangeRemoved
+		}
+
+		diff.Changes = append(diff.Changes, change)
+		if impactSeverity[change.Impact] > impactSeverity[diff.Impact] {
+			diff.Impact = change.Impact
+		}
+	}
+
+	return diff, nil
+}
+
+// knownValue returns the value at path if it's a field of the Configuration structs.
+func knownValue(cfg *Configuration, path string) (any, bool) {
+	lang, _, _ := strings.Cut(path, ".")
+	if _, ok := cfg.Languages[lang]; ok {
+		return nil, false
+	}
+
+	value, err := cfg.Get(path)
+	if err != nil || value == nil {
+		return nil, false
+	}
+
+	// Compare in the same form as the marshaled values
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), true
+	case reflect.Bool:
+		return v.Bool(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(v.Int()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return nil, false
+}
+
+func fieldImpact(path string, impacts map[string]Impact) Impact {
+	if impact, ok := impacts[path]; ok {
+		return impact
+	}
+
+	parts := strings.Split(path, ".")
+
+	t := reflect.TypeOf(Configuration{})
+	if _, ok := structFieldInfo(t, parts[0]); !ok {
+		if impact, ok := languageFieldImpacts[strings.Join(parts[1:], ".")]; ok {
+			return impact
+		}
+		return ImpactMinor
+	}
+
+	// The closest impact tag to the field applies
+	impact := ImpactMinor
+	for _, part := range parts {
+		sf, ok := structFieldInfo(t, part)
+		if !ok {
+			break
+		}
+		if tag, ok := sf.Tag.Lookup("impact"); ok {
+			impact = Impact(tag)
+		}
+
+		t = sf.Type
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			break
+		}
+	}
+
+	return impact
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/speakeasy-api/openapi/pointer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	base := func() *Configuration {
+		cfg, err := GetDefaultConfig(false, nil, nil)
+		require.NoError(t, err)
+		cfg.Languages["go"] = LanguageConfig{
+			Version: "1.0.0",
+			Cfg: map[string]any{
+				"packageName":      "openapi",
+				"maxMethodParams":  4,
+				"responseFormat":   "flat",
+				"imports":          map[string]any{"option": "openapi"},
+				"additionalOption": true,
+			},
+		}
+		return cfg
+	}
+
+	tests := []struct {
+		name       string
+		mutate     func(cfg *Configuration)
+		fields     []SDKGenConfigField
+		want       []ConfigChange
+		wantImpact Impact
+	}{
+		{
+			name:       "no changes",
+			mutate:     func(cfg *Configuration) {},
+			want:       []ConfigChange{},
+			wantImpact: ImpactNone,
+		},
+		{
+			name: "flipping a fix is breaking",
+			mutate: func(cfg *Configuration) {
+				cfg.Generation.Fixes.SecurityFeb2025 = true
+			},
+			want: []ConfigChange{
+				{Type: ConfigChangeChanged, Path: "generation.fixes.securityFeb2025", Before: false, After: true, Impact: ImpactMajor},
+			},
+			wantImpact: ImpactMajor,
+		},
+		{
+			name: "generation and language changes",
+			mutate: func(cfg *Configuration) {
+				cfg.Generation.SDKClassName = "MySDK"
+				cfg.Generation.VersioningStrategy = VersioningStrategyManual
+				cfg.Generation.InferSSEOverload = true
+				goCfg := cfg.Languages["go"]
+				goCfg.Version = "1.1.0"
+				goCfg.Cfg["packageName"] = "sdk"
+				goCfg.Cfg["newOption"] = "value"
+				delete(goCfg.Cfg, "additionalOption")
+				cfg.Languages["go"] = goCfg
+			},
+			want: []ConfigChange{
+				{Type: ConfigChangeChanged, Path: "generation.inferSSEOverload", Before: false, After: true, Impact: ImpactMinor},
+				{Type: ConfigChangeChanged, Path: "generation.sdkClassName", Before: "SDK", After: "MySDK", Impact: ImpactMajor},
+				{Type: ConfigChangeChanged, Path: "generation.versioningStrategy", Before: "automatic", After: "manual", Impact: ImpactNone},
+				{Type: ConfigChangeRemoved, Path: "go.additionalOption", Before: true, Impact: ImpactMinor},
+				{Type: ConfigChangeAdded, Path: "go.newOption", After: "value", Impact: ImpactMinor},
+				{Type: ConfigChangeChanged, Path: "go.packageName", Before: "openapi", After: "sdk", Impact: ImpactMajor},
+				{Type: ConfigChangeChanged, Path: "go.version", Before: "1.0.0", After: "1.1.0", Impact: ImpactNone},
+			},
+			wantImpact: ImpactMajor,
+		},
+		{
+			name: "impacts from fields take precedence",
+			mutate: func(cfg *Configuration) {
+				cfg.Languages["go"].Cfg["responseFormat"] = "envelope"
+				cfg.Generation.UsageSnippets.SDKInitStyle = SDKInitStyleBuilder
+			},
+			fields: []SDKGenConfigField{
+				{Name: "responseFormat", Language: pointer.From("go"), Impact: ImpactMajor},
+				{Name: "maxMethodParams", Language: pointer.From("go")},
+			},
+			want: []ConfigChange{
+				{Type: ConfigChangeChanged, Path: "generation.usageSnippets.sdkInitStyle", Before: "constructor", After: "builder", Impact: ImpactNone},
+				{Type: ConfigChangeChanged, Path: "go.responseFormat", Before: "flat", After: "envelope", Impact: ImpactMajor},
+			},
+			wantImpact: ImpactMajor,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := base()
+			updated := base()
+			tt.mutate(updated)
+
+			diff, err := Diff(old, updated, tt.fields...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, diff.Changes)
+			assert.Equal(t, tt.wantImpact, diff.Impact)
+			assert.Equal(t, tt.wantImpact == ImpactMajor, diff.Breaking())
+		})
+	}
+}
+
+func TestConfigDiff_Render(t *testing.T) {
+	diff := &ConfigDiff{
+		Changes: []ConfigChange{
+			{Type: ConfigChangeChanged, Path: "generation.sdkClassName", Before: "SDK", After: "MySDK", Impact: ImpactMajor},
+			{Type: ConfigChangeAdded, Path: "go.newOption", After: "value", Impact: ImpactMinor},
+			{Type: ConfigChangeRemoved, Path: "go.maxMethodParams", Before: 4, Impact: ImpactMinor},
+		},
+		Impact: ImpactMajor,
+	}
+
+	assert.Equal(t, `gen.yaml changes (major impact):
+  ~ generation.sdkClassName: "SDK" -> "MySDK" [major]
+  + go.newOption: "value" [minor]
+  - go.maxMethodParams: 4 [minor]
+`, diff.String())
+
+	data, err := json.Marshal(diff)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+  "changes": [
+    {"type": "changed", "path": "generation.sdkClassName", "before": "SDK", "after": "MySDK", "impact": "major"},
+    {"type": "added", "path": "go.newOption", "after": "value", "impact": "minor"},
+    {"type": "removed", "path": "go.maxMethodParams", "before": 4, "impact": "minor"}
+  ],
+  "impact": "major"
+}`, string(data))
+
+	assert.Equal(t, "No changes to gen.yaml\n", (&ConfigDiff{Impact: ImpactNone}).String())
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Explanation describes the resolved value of a configuration field and where it came from.
+type Explanation struct {
+	Path      string
+	Value     any
+	Source    ValueSource
+	Overrides []ValueSource // Lower precedence layers that also set the value, in order of increasing precedence
+}
+
+func (e *Explanation) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s = %s\n", e.Path, formatChangeValue(e.Value))
+	fmt.Fprintf(&sb, "  set by %s\n", e.Source)
+	for i := len(e.Overrides) - 1; i >= 0; i-- {
+		fmt.Fprintf(&sb, "  overrides %s\n", e.Overrides[i])
+	}
+	return sb.String()
+}
+
+// Explain reports the resolved value of a generation or language field, for example "generation.fixes.nameResolutionFeb2025"
+// or "go.maxMethodParams", along with the layer, file and line it was set by. The config must have been loaded WithProvenance.
+func (c *Config) Explain(path string) (*Explanation, error) {
+	if c.provenance == nil {
+		return nil, errors.New("provenance wasn't recorded, load the config WithProvenance to explain values")
+	}
+
+	value, err := c.Config.Get(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Provenance is recorded under the yaml keys, while Get also matches keys case-insensitively
+	path = canonicalPath(c.Config, path)
+
+	e := &Explanation{
+		Path:  path,
+		Value: value,
+	}
+
+	sources := c.provenance[path]
+	if len(sources) == 0 {
+		e.Source = ValueSource{Layer: SourceDefault}
+		if _, ok := c.Config.Languages[strings.Split(path, ".")[0]]; ok {
+			e.Source.Layer = SourceLanguageDefault
+		}
+		return e, nil
+	}
+
+	e.Source = sources[len(sources)-1]
+	if len(sources) > 1 {
+		e.Overrides = slices.Clone(sources[:len(sources)-1])
+	}
+
+	return e, nil
+}
+
+// resolveProvenance combines the positions recorded for each file layer with the values populated by defaults
+// and any changes made by the transformer, which are found by comparing the config before and after it ran.
+func resolveProvenance(layers *configLayers, defaults *Configuration, beforeTransform map[string]any, cfg *Configuration) (map[string][]ValueSource, error) {
+	provenance := layers.provenance()
+
+	defaultValues, err := flattenConfig(defaults)
+	if err != nil {
+		return nil, err
+	}
+
+	for p := range defaultValues {
+		source := ValueSource{Layer: SourceDefault}
+		if _, ok := defaults.Languages[strings.Split(p, ".")[0]]; ok {
+			source.Layer = SourceLanguageDefault
+		}
+		provenance[p] = append([]ValueSource{source}, provenance[p]...)
+	}
+
+	afterTransform, err := flattenConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, values := range []map[string]any{beforeTransform, afterTransform} {
+		for p := range values {
+			before, beforeOK := beforeTransform[p]
+			after, afterOK := afterTransform[p]
+			if beforeOK == afterOK && reflect.DeepEqual(before, after) {
+				continue
+			}
+
+			sources := provenance[p]
+			if len(sources) > 0 && sources[len(sources)-1].Layer == SourceTransformer {
+				continue
+			}
+			provenance[p] = append(sources, ValueSource{Layer: SourceTransformer})
+		}
+	}
+
+	return provenance, nil
+}
+
+// flattenConfig returns the leaf values of cfg keyed by their dotted paths.
+func flattenConfig(cfg *Configuration) (map[string]any, error) {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]any
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+
+	flat := map[string]any{}
+	flattenMap("", m, flat)
+	return flat, nil
+}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/speakeasy-api/sdk-gen-config/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Explain(t *testing.T) {
+	dir := t.TempDir()
+	speakeasyDir := filepath.Join(dir, ".speakeasy")
+	configPath := filepath.Join(speakeasyDir, "gen.yaml")
+	localPath := filepath.Join(speakeasyDir, "gen.local.yaml")
+
+	testutils.CreateTempFile(t, speakeasyDir, "gen.yaml", `configVersion: 2.0.0
+generation:
+  sdkClassName: speakeasy
+  fixes:
+    nameResolutionFeb2025: false
+  baseServerUrl: https://api.example.com
+go:
+  version: 1.0.0
+`)
+	testutils.CreateTempFile(t, speakeasyDir, "gen.lock", testutils.ReadTestFile(t, "v200-gen.lock"))
+	testutils.CreateTempFile(t, speakeasyDir, "gen.local.yaml", `generation:
+  fixes:
+    nameResolutionFeb2025: true
+`)
+
+	cfg, err := Load(dir,
+		WithLanguages("go"),
+		WithProvenance(),
+		WithLanguageDefaultFunc(func(lang string, newSDK bool) (*LanguageConfig, error) {
+			return &LanguageConfig{
+				Version: "0.0.1",
+				Cfg:     map[string]any{"packageName": "openapi"},
+			}, nil
+		}),
+		WithTransformerFunc(func(c *Config) (*Config, error) {
+			c.Config.Generation.SDKClassName = "Transformed"
+			return c, nil
+		}),
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		path string
+		want *Explanation
+	}{
+		{
+			path: "generation.fixes.nameResolutionFeb2025",
+			want: &Explanation{
+				Path:   "generation.fixes.nameResolutionFeb2025",
+				Value:  true,
+				Source: ValueSource{Layer: SourceLocal, File: localPath, Line: 3, Column: 5},
+				Overrides: []ValueSource{
+					{Layer: SourceDefault},
+					{Layer: SourceFile, File: configPath, Line: 5, Column: 5},
+				},
+			},
+		},
+		{
+			path: "generation.usageSnippets.sdkInitStyle",
+			want: &Explanation{
+				Path:   "generation.usageSnippets.sdkInitStyle",
+				Value:  SDKInitStyle("constructor"),
+				Source: ValueSource{Layer: SourceDefault},
+			},
+		},
+		{
+			path: "generation.sdkClassName",
+			want: &Explanation{
+				Path:   "generation.sdkClassName",
+				Value:  "Transformed",
+				Source: ValueSource{Layer: SourceTransformer},
+				Overrides: []ValueSource{
+					{Layer: SourceDefault},
+					{Layer: SourceFile, File: configPath, Line: 3, Column: 3},
+				},
+			},
+		},
+		{
+			path: "go.version",
+			want: &Explanation{
+				Path:      "go.version",
+				Value:     "1.0.0",
+				Source:    ValueSource{Layer: SourceFile, File: configPath, Line: 8, Column: 3},
+				Overrides: []ValueSource{{Layer: SourceLanguageDefault}},
+			},
+		},
+		{
+			path: "go.packageName",
+			want: &Explanation{
+				Path:   "go.packageName",
+				Value:  "openapi",
+				Source: ValueSource{Layer: SourceLanguageDefault},
+			},
+		},
+		{
+			path: "go.maxMethodParams",
+			want: &Explanation{
+				Path:   "go.maxMethodParams",
+				Source: ValueSource{Layer: SourceLanguageDefault},
+			},
+		},
+		{
+			path: "generation.maintainOpenAPIOrder",
+			want: &Explanation{
+				Path:   "generation.maintainOpenAPIOrder",
+				Value:  false,
+				Source: ValueSource{Layer: SourceDefault},
+			},
+		},
+	}
+	// Paths are matched case-insensitively like Get and reported by their yaml keys
+	tests = append(tests, []struct {
+		path string
+		want *Explanation
+	}{
+		{
+			path: "generation.baseServerURL",
+			want: &Explanation{
+				Path:   "generation.baseServerUrl",
+				Value:  "https://api.example.com",
+				Source: ValueSource{Layer: SourceFile, File: configPath, Line: 6, Column: 3},
+			},
+		},
+		{
+			path: "generation.SDKClassName",
+			want: tests[2].want,
+		},
+	}...)
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := cfg.Explain(tt.path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	source, ok := cfg.Source("generation.baseServerURL")
+	assert.True(t, ok)
+	assert.Equal(t, ValueSource{Layer: SourceFile, File: configPath, Line: 6, Column: 3}, source)
+
+	_, err = cfg.Explain("generation.fixes.unknownFix")
+	assert.ErrorIs(t, err, ErrUnknownPath)
+
+	_, err = cfg.Explain("go.notAnOption")
+	assert.ErrorIs(t, err, ErrUnknownPath)
+}
+
+func TestConfig_Explain_RequiresProvenance(t *testing.T) {
+	dir := t.TempDir()
+	testutils.CreateTempFile(t, filepath.Join(dir, ".speakeasy"), "gen.yaml", testutils.ReadTestFile(t, "v200-gen.yaml"))
+
+	cfg, err := Load(dir, WithLanguages("go"))
+	require.NoError(t, err)
+
+	_, err = cfg.Explain("generation.sdkClassName")
+	assert.EqualError(t, err, "provenance wasn't recorded, load the config WithProvenance to explain values")
+}
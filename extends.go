@@ -0,0 +1,283 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var ErrExtendsCycle = errors.New("extends cycle detected")
+
+type SourceLayer string
+
+// Layers in order of increasing precedence
+const (
+	// SourceDefault is a value populated by GetDefaultConfig
+	SourceDefault SourceLayer = "default"
+	// SourceLanguageDefault is a value populated by the GetLanguageDefaultFunc
+	SourceLanguageDefault SourceLayer = "languageDefault"
+	// SourceExtends is a value set by one of the base configs listed in extends
+	SourceExtends SourceLayer = "extends"
+	// SourceFile is a value set by gen.yaml itself
+	SourceFile SourceLayer = "file"
+	// SourceLocal is a value set by gen.local.yaml
+	SourceLocal SourceLayer = "local"
+	// SourceTransformer is a value changed by the TransformerFunc
+	SourceTransformer SourceLayer = "transformer"
+)
+
+// ValueSource describes where a resolved configuration value was set.
+type ValueSource struct {
+	Layer  SourceLayer
+	File   string
+	Line   int
+	Column int
+}
+
+func (s ValueSource) String() string {
+	if s.File == "" {
+		return string(s.Layer)
+	}
+	return fmt.Sprintf("%s (%s:%d:%d)", s.Layer, s.File, s.Line, s.Column)
+}
+
+// WithProvenance records where each value in gen.yaml was set while loading, see Config.Source and Config.Explain.
+func WithProvenance() Option {
+	return func(o *options) {
+		o.provenance = true
+	}
+}
+
+// Source returns where the value at path was set, for example "generation.fixes.nameResolutionFeb2025" or "go.version".
+// The config must have been loaded WithProvenance. False is returned for values that weren't explicitly set,
+// see Explain for values populated by defaults.
+func (c *Config) Source(path string) (ValueSource, bool) {
+	sources := c.provenance[canonicalPath(c.Config, path)]
+	if len(sources) == 0 {
+		return ValueSource{}, false
+	}
+	return sources[len(sources)-1], true
+}
+
+// configLayer is a single file contributing values to the resolved gen.yaml.
+type configLayer struct {
+	layer     SourceLayer
+	path      string
+	data      []byte
+	values    map[string]any
+	positions map[string]ValueSource
+}
+
+// configLayers holds the files that are merged to produce the resolved gen.yaml, in order of increasing precedence.
+type configLayers struct {
+	bases []*configLayer
+	file  *configLayer
+	local *configLayer
+}
+
+func newConfigLayer(layer SourceLayer, path string, data []byte) (*configLayer, error) {
+	l := &configLayer{
+		layer:     layer,
+		path:      path,
+		data:      data,
+		values:    map[string]any{},
+		positions: map[string]ValueSource{},
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("could not unmarshal %s: %w", filepath.Base(path), err)
+	}
+	if doc.Kind != 0 {
+		if err := doc.Decode(&l.values); err != nil {
+			return nil, fmt.Errorf("could not unmarshal %s: %w", filepath.Base(path), err)
+		}
+		if l.values == nil {
+			l.values = map[string]any{}
+		}
+	}
+
+	walkMappingKeys(&doc, "", func(keyPath string, key *yaml.Node) {
+		l.positions[strings.TrimPrefix(keyPath, ".")] = ValueSource{
+			Layer:  layer,
+			File:   path,
+			Line:   key.Line,
+			Column: key.Column,
+		}
+	})
+
+	return l, nil
+}
+
+// loadConfigLayers resolves the base configs extended by the gen.yaml at configPath and the gen.local.yaml next to it.
+func loadConfigLayers(configPath string, data []byte, o *options) (*configLayers, error) {
+	file, err := newConfigLayer(SourceFile, configPath, data)
+	if err != nil {
+		return nil, err
+	}
+
+	bases, err := loadExtends(configPath, file.values, []string{filepath.Clean(configPath)}, o)
+	if err != nil {
+		return nil, err
+	}
+
+	local, err := readLocalLayer(configPath, o)
+	if err != nil {
+		return nil, err
+	}
+
+	return &configLayers{
+		bases: bases,
+		file:  file,
+		local: local,
+	}, nil
+}
+
+// loadExtends returns the layers for the configs extended by the config at path, with any configs they extend themselves
+// preceding them. stack holds the chain of configs currently being resolved and is used to detect cycles.
+func loadExtends(path string, values map[string]any, stack []string, o *options) ([]*configLayer, error) {
+	raw, ok := values["extends"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	extends, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("extends in %s must be a list of paths", filepath.Base(path))
+	}
+
+	var layers []*configLayer
+	for _, e := range extends {
+		ext, ok := e.(string)
+		if !ok || ext == "" {
+			return nil, fmt.Errorf("extends in %s must be a list of paths", filepath.Base(path))
+		}
+
+		extPath := ext
+		if !filepath.IsAbs(extPath) {
+			extPath = filepath.Join(filepath.Dir(path), extPath)
+		}
+		extPath = filepath.Clean(extPath)
+
+		if slices.Contains(stack, extPath) {
+			return nil, fmt.Errorf("%w: %s", ErrExtendsCycle, strings.Join(append(slices.Clone(stack), extPath), " -> "))
+		}
+
+		data, err := readFile(extPath, o)
+		if err != nil {
+			return nil, fmt.Errorf("could not read extended config %s: %w", ext, err)
+		}
+
+		layer, err := newConfigLayer(SourceExtends, extPath, data)
+		if err != nil {
+			return nil, err
+		}
+
+		nested, err := loadExtends(extPath, layer.values, append(slices.Clone(stack), extPath), o)
+		if err != nil {
+			return nil, err
+		}
+
+		// The extends of a base config are resolved here and don't carry over into the merged config
+		delete(layer.values, "extends")
+		for p := range layer.positions {
+			if p == "extends" || strings.HasPrefix(p, "extends[") {
+				delete(layer.positions, p)
+			}
+		}
+
+		layers = append(layers, nested...)
+		layers = append(layers, layer)
+	}
+
+	return layers, nil
+}
+
+func (l *configLayers) all() []*configLayer {
+	all := append(slices.Clone(l.bases), l.file)
+	if l.local != nil {
+		all = append(all, l.local)
+	}
+	return all
+}
+
+// validateSchema validates each file against the schema, reporting the violations in all of them together.
+func (l *configLayers) validateSchema() error {
+	var diagnostics []SchemaDiagnostic
+	for _, layer := range l.all() {
+		d, err := configSchemaDiagnostics(layer.path, layer.data, layer != l.file)
+		if err != nil {
+			return err
+		}
+		diagnostics = append(diagnostics, d...)
+	}
+
+	if len(diagnostics) == 0 {
+		return nil
+	}
+	return &SchemaValidationError{Diagnostics: diagnostics}
+}
+
+// layered returns true if any values come from files other than gen.yaml itself.
+func (l *configLayers) layered() bool {
+	return l != nil && (len(l.bases) > 0 || l.local != nil)
+}
+
+func (l *configLayers) merged() map[string]any {
+	merged := map[string]any{}
+	for _, layer := range l.all() {
+		merged = mergeLayer(merged, layer.values)
+	}
+	return merged
+}
+
+func (l *configLayers) baseValues() map[string]any {
+	merged := map[string]any{}
+	for _, layer := range l.bases {
+		merged = mergeLayer(merged, layer.values)
+	}
+	return merged
+}
+
+// provenance returns the positions each key in the merged config was set at, in order of increasing precedence.
+func (l *configLayers) provenance() map[string][]ValueSource {
+	provenance := map[string][]ValueSource{}
+	for _, layer := range l.all() {
+		// A layer replacing a sequence or scalar replaces everything beneath it
+		for p := range layer.positions {
+			if _, isMap := lookupPath(layer.values, p).(map[string]any); isMap {
+				continue
+			}
+			for existing := range provenance {
+				if strings.HasPrefix(existing, p+".") || strings.HasPrefix(existing, p+"[") {
+					delete(provenance, existing)
+				}
+			}
+		}
+
+		for p, source := range layer.positions {
+			provenance[p] = append(provenance[p], source)
+		}
+	}
+	return provenance
+}
+
+// lookupPath returns the value at a dotted path in m, or nil for paths into sequences.
+func lookupPath(m map[string]any, path string) any {
+	var current any = m
+	for _, part := range strings.Split(path, ".") {
+		if strings.Contains(part, "[") {
+			return nil
+		}
+		cm, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+		current = cm[part]
+	}
+	return current
+}
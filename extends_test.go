@@ -0,0 +1,105 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/speakeasy-api/sdk-gen-config/lockfile"
+	"github.com/speakeasy-api/sdk-gen-config/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Extends(t *testing.T) {
+	getUUID = func() string {
+		return "123"
+	}
+	lockfile.GetUUID = getUUID
+
+	dir := t.TempDir()
+	sharedDir := filepath.Join(dir, "shared")
+	speakeasyDir := filepath.Join(dir, "sdk", ".speakeasy")
+
+	testutils.CreateTempFile(t, sharedDir, "fixes.yaml", `generation:
+  fixes:
+    nameResolutionFeb2025: true
+    securityFeb2025: true
+  auth:
+    oAuth2ClientCredentialsEnabled: true
+`)
+	testutils.CreateTempFile(t, sharedDir, "base.yaml", `extends:
+  - fixes.yaml
+generation:
+  fixes:
+    securityFeb2025: false
+  usageSnippets:
+    sdkInitStyle: builder
+  sdkClassName: Shared
+`)
+	testutils.CreateTempFile(t, speakeasyDir, "gen.yaml", `configVersion: 2.0.0
+extends:
+  - ../../shared/base.yaml
+generation:
+  sdkClassName: speakeasy
+go:
+  version: 1.0.0
+`)
+	testutils.CreateTempFile(t, speakeasyDir, "gen.lock", testutils.ReadTestFile(t, "v200-gen.lock"))
+
+	cfg, err := Load(filepath.Join(dir, "sdk"), WithUpgradeFunc(testUpdateLang), WithLanguages("go"), WithProvenance())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"../../shared/base.yaml"}, cfg.Config.Extends)
+	assert.True(t, cfg.Config.Generation.Fixes.NameResolutionFeb2025)
+	assert.False(t, cfg.Config.Generation.Fixes.SecurityFeb2025)
+	assert.True(t, cfg.Config.Generation.Auth.OAuth2ClientCredentialsEnabled)
+	assert.Equal(t, SDKInitStyle("builder"), cfg.Config.Generation.UsageSnippets.SDKInitStyle)
+	assert.Equal(t, "speakeasy", cfg.Config.Generation.SDKClassName)
+
+	source, ok := cfg.Source("generation.fixes.nameResolutionFeb2025")
+	require.True(t, ok)
+	assert.Equal(t, ValueSource{Layer: SourceExtends, File: filepath.Join(sharedDir, "fixes.yaml"), Line: 3, Column: 5}, source)
+
+	source, ok = cfg.Source("generation.fixes.securityFeb2025")
+	require.True(t, ok)
+	assert.Equal(t, ValueSource{Layer: SourceExtends, File: filepath.Join(sharedDir, "base.yaml"), Line: 5, Column: 5}, source)
+
+	source, ok = cfg.Source("generation.sdkClassName")
+	require.True(t, ok)
+	assert.Equal(t, ValueSource{Layer: SourceFile, File: filepath.Join(speakeasyDir, "gen.yaml"), Line: 5, Column: 3}, source)
+
+	_, ok = cfg.Source("generation.maintainOpenAPIOrder")
+	assert.False(t, ok)
+
+	// Values provided by the base configs aren't written into gen.yaml
+	data, err := os.ReadFile(filepath.Join(speakeasyDir, "gen.yaml"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "nameResolutionFeb2025")
+	assert.NotContains(t, string(data), "oAuth2ClientCredentialsEnabled")
+	assert.NotContains(t, string(data), "sdkInitStyle")
+	assert.Contains(t, string(data), "extends:\n  - ../../shared/base.yaml\n")
+	assert.Contains(t, string(data), "sdkClassName: speakeasy\n")
+}
+
+func TestLoad_ExtendsCycle(t *testing.T) {
+	dir := t.TempDir()
+	speakeasyDir := filepath.Join(dir, ".speakeasy")
+
+	testutils.CreateTempFile(t, speakeasyDir, "a.yaml", `extends:
+  - b.yaml
+`)
+	testutils.CreateTempFile(t, speakeasyDir, "b.yaml", `extends:
+  - a.yaml
+`)
+	testutils.CreateTempFile(t, speakeasyDir, "gen.yaml", `configVersion: 2.0.0
+extends:
+  - a.yaml
+generation:
+  sdkClassName: speakeasy
+`)
+
+	_, err := Load(dir, WithLanguages("go"))
+	require.ErrorIs(t, err, ErrExtendsCycle)
+	assert.Contains(t, err.Error(), "a.yaml -> "+filepath.Join(speakeasyDir, "b.yaml")+" -> "+filepath.Join(speakeasyDir, "a.yaml"))
+}
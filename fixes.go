@@ -0,0 +1,208 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+)
+
+const fixesBaselineFormat = "2006-01"
+
+// Fix describes a generator fix that can be enabled in the fixes section of gen.yaml.
+type Fix struct {
+	Name        string    // The key of the fix in gen.yaml, for example "nameResolutionFeb2025"
+	Introduced  time.Time // The month the fix was introduced, taken from the suffix of its name
+	Description string
+
+	index []int
+}
+
+// Baseline returns the fixes baseline that first includes the fix, for example "2025-02".
+func (f Fix) Baseline() string {
+	return f.Introduced.Format(fixesBaselineFormat)
+}
+
+var availableFixes = sync.OnceValue(func() []Fix {
+	t := reflect.TypeOf(Fixes{})
+
+	var fixes []Fix
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Type.Kind() != reflect.Bool {
+			continue
+		}
+
+		name, _ := yamlFieldName(f)
+		if len(name) < len("Jan2006") {
+			panic(fmt.Sprintf("fix %s must be suffixed with the month it was introduced", f.Name))
+		}
+
+		introduced, err := time.Parse("Jan2006", name[len(name)-len("Jan2006"):])
+		if err != nil {
+			panic(fmt.Sprintf("fix %s must be suffixed with the month it was introduced: %v", f.Name, err))
+		}
+
+		fixes = append(fixes, Fix{
+			Name:        name,
+			Introduced:  introduced,
+			Description: f.Tag.Get("description"),
+			index:       f.Index,
+		})
+	}
+
+	slices.SortStableFunc(fixes, func(a, b Fix) int {
+		return a.Introduced.Compare(b.Introduced)
+	})
+
+	return fixes
+})
+
+// AvailableFixes returns every fix declared on Fixes, in the order they were introduced.
+func AvailableFixes() []Fix {
+	return slices.Clone(availableFixes())
+}
+
+// LatestFixesBaseline returns the fixes baseline that includes every available fix.
+func LatestFixesBaseline() string {
+	fixes := availableFixes()
+	return fixes[len(fixes)-1].Baseline()
+}
+
+// FixesUpTo returns the fixes introduced up to and including the baseline month, in the form YYYY-MM.
+func FixesUpTo(baseline string) ([]Fix, error) {
+	cutoff, err := parseFixesBaseline(baseline)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixes []Fix
+	for _, fix := range availableFixes() {
+		if fix.Introduced.After(cutoff) {
+			break
+		}
+		fixes = append(fixes, fix)
+	}
+
+	return fixes, nil
+}
+
+// Enabled returns true if the named fix is enabled.
+func (f *Fixes) Enabled(name string) bool {
+	if f == nil {
+		return false
+	}
+
+	for _, fix := range availableFixes() {
+		if fix.Name == name {
+			return reflect.ValueOf(f).Elem().FieldByIndex(fix.index).Bool()
+		}
+	}
+
+	return false
+}
+
+// Missing returns the fixes introduced up to and including the baseline that aren't enabled.
+// An empty baseline reports against every available fix.
+func (f *Fixes) Missing(baseline string) ([]Fix, error) {
+	if baseline == "" {
+		baseline = LatestFixesBaseline()
+	}
+
+	fixes, err := FixesUpTo(baseline)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []Fix
+	for _, fix := range fixes {
+		if !f.Enabled(fix.Name) {
+			missing = append(missing, fix)
+		}
+	}
+
+	return missing, nil
+}
+
+// EnableUpTo enables every fix introduced up to and including the baseline and returns the fixes it enabled.
+// Fixes are never disabled.
+func (f *Fixes) EnableUpTo(baseline string) ([]Fix, error) {
+	return f.enableUpTo(baseline, nil)
+}
+
+func (f *Fixes) enableUpTo(baseline string, optOut []string) ([]Fix, error) {
+	missing, err := f.Missing(baseline)
+	if err != nil {
+		return nil, err
+	}
+
+	var enabled []Fix
+	v := reflect.ValueOf(f).Elem()
+	for _, fix := range missing {
+		if slices.Contains(optOut, fix.Name) {
+			continue
+		}
+		v.FieldByIndex(fix.index).SetBool(true)
+		enabled = append(enabled, fix)
+	}
+
+	return enabled, nil
+}
+
+// FixEnabled returns true if the named fix is enabled in fixes, or was introduced up to and including the configured
+// fixesBaseline and isn't listed in fixesOptOut. The config isn't modified, so the baseline is resolved without
+// writing the fixes it implies back to gen.yaml.
+//
+// Fixes set to false in gen.yaml don't opt out of the baseline, as every fix is written to gen.yaml whether it was
+// set or not.
+func (g *Generation) FixEnabled(name string) bool {
+	if g.Fixes.Enabled(name) {
+		return true
+	}
+	if g.FixesBaseline == "" || slices.Contains(g.FixesOptOut, name) {
+		return false
+	}
+
+	fixes, err := FixesUpTo(g.FixesBaseline)
+	if err != nil {
+		return false
+	}
+
+	return slices.ContainsFunc(fixes, func(fix Fix) bool {
+		return fix.Name == name
+	})
+}
+
+// ApplyFixesBaseline enables the fixes implied by the configured fixesBaseline, if any, and returns the fixes it enabled.
+// Fixes listed in fixesOptOut are left disabled. Load doesn't apply the baseline, call this to write the fixes it
+// enables to gen.yaml.
+func (g *Generation) ApplyFixesBaseline() ([]Fix, error) {
+	if g.FixesBaseline == "" {
+		return nil, nil
+	}
+
+	if g.Fixes == nil {
+		g.Fixes = &Fixes{}
+	}
+
+	return g.Fixes.enableUpTo(g.FixesBaseline, g.FixesOptOut)
+}
+
+// validateFixesOptOut returns an error if optOut names a fix that doesn't exist.
+func validateFixesOptOut(optOut []string) error {
+	for _, name := range optOut {
+		if !slices.ContainsFunc(availableFixes(), func(fix Fix) bool { return fix.Name == name }) {
+			return fmt.Errorf("%w: generation.fixesOptOut: %q is not a known fix", ErrInvalidValue, name)
+		}
+	}
+	return nil
+}
+
+func parseFixesBaseline(baseline string) (time.Time, error) {
+	cutoff, err := time.Parse(fixesBaselineFormat, baseline)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: generation.fixesBaseline: %q must be a year and month such as 2025-04", ErrInvalidValue, baseline)
+	}
+	return cutoff, nil
+}
@@ -0,0 +1,191 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/speakeasy-api/sdk-gen-config/lockfile"
+	"github.com/speakeasy-api/sdk-gen-config/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func fixNames(fixes []Fix) []string {
+	names := make([]string, 0, len(fixes))
+	for _, fix := range fixes {
+		names = append(names, fix.Name)
+	}
+	return names
+}
+
+func TestAvailableFixes(t *testing.T) {
+	fixes := AvailableFixes()
+	require.NotEmpty(t, fixes)
+
+	for i := 1; i < len(fixes); i++ {
+		assert.False(t, fixes[i].Introduced.Before(fixes[i-1].Introduced), "%s is out of order", fixes[i].Name)
+	}
+
+	assert.Equal(t, "nameResolutionDec2023", fixes[0].Name)
+	assert.Equal(t, "2023-12", fixes[0].Baseline())
+	assert.NotEmpty(t, fixes[0].Description)
+	assert.Equal(t, fixes[len(fixes)-1].Baseline(), LatestFixesBaseline())
+}
+
+func TestFixesUpTo(t *testing.T) {
+	tests := []struct {
+		name     string
+		baseline string
+		want     []string
+		wantErr  string
+	}{
+		{
+			name:     "before any fixes",
+			baseline: "2023-01",
+			want:     []string{},
+		},
+		{
+			name:     "includes the baseline month",
+			baseline: "2024-04",
+			want:     []string{"nameResolutionDec2023", "parameterOrderingFeb2024", "requestResponseComponentNamesFeb2024"},
+		},
+		{
+			name:     "invalid baseline",
+			baseline: "April 2025",
+			wantErr:  `invalid configuration value: generation.fixesBaseline: "April 2025" must be a year and month such as 2025-04`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixes, err := FixesUpTo(tt.baseline)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, fixNames(fixes))
+		})
+	}
+}
+
+func TestFixes_MissingAndEnableUpTo(t *testing.T) {
+	fixes := &Fixes{
+		NameResolutionDec2023:    true,
+		ParameterOrderingFeb2024: false,
+		SecurityFeb2025:          true,
+	}
+
+	missing, err := fixes.Missing("2025-02")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"parameterOrderingFeb2024", "requestResponseComponentNamesFeb2024", "nameResolutionFeb2025"}, fixNames(missing))
+
+	all, err := fixes.Missing("")
+	require.NoError(t, err)
+	assert.Greater(t, len(all), len(missing))
+
+	enabled, err := fixes.EnableUpTo("2025-02")
+	require.NoError(t, err)
+	assert.Equal(t, fixNames(missing), fixNames(enabled))
+	assert.True(t, fixes.ParameterOrderingFeb2024)
+	assert.True(t, fixes.NameResolutionFeb2025)
+	assert.False(t, fixes.SharedErrorComponentsApr2025)
+
+	missing, err = fixes.Missing("2025-02")
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+}
+
+func TestFixes_EnableUpTo_KeepsOptedOut(t *testing.T) {
+	var gen Generation
+	require.NoError(t, yaml.Unmarshal([]byte(`fixesBaseline: 2025-02
+fixesOptOut:
+  - parameterOrderingFeb2024
+fixes:
+  requestResponseComponentNamesFeb2024: false
+  securityFeb2025: true
+`), &gen))
+
+	assert.False(t, gen.FixEnabled("parameterOrderingFeb2024"))
+	assert.True(t, gen.FixEnabled("requestResponseComponentNamesFeb2024"), "fixes set to false don't opt out")
+	assert.True(t, gen.FixEnabled("securityFeb2025"))
+	assert.False(t, gen.FixEnabled("sharedErrorComponentsApr2025"))
+
+	enabled, err := gen.ApplyFixesBaseline()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"nameResolutionDec2023", "requestResponseComponentNamesFeb2024", "nameResolutionFeb2025"}, fixNames(enabled))
+	assert.False(t, gen.Fixes.ParameterOrderingFeb2024)
+
+	missing, err := gen.Fixes.Missing("2025-02")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"parameterOrderingFeb2024"}, fixNames(missing))
+
+	// Loaded fixes compare equal to constructed ones
+	var fixes Fixes
+	require.NoError(t, yaml.Unmarshal([]byte("securityFeb2025: true\nparameterOrderingFeb2024: false\n"), &fixes))
+	assert.Equal(t, Fixes{SecurityFeb2025: true}, fixes)
+}
+
+func TestLoad_FixesBaseline(t *testing.T) {
+	getUUID = func() string {
+		return "123"
+	}
+	lockfile.GetUUID = getUUID
+
+	dir := t.TempDir()
+	speakeasyDir := filepath.Join(dir, ".speakeasy")
+	genYaml := `configVersion: 2.0.0
+generation:
+  sdkClassName: speakeasy
+  fixesBaseline: 2025-04
+  fixesOptOut:
+    - securityFeb2025
+  fixes:
+    requestResponseComponentNamesFeb2024: false
+go:
+  version: 1.0.0
+`
+	testutils.CreateTempFile(t, speakeasyDir, "gen.yaml", genYaml)
+	testutils.CreateTempFile(t, speakeasyDir, "gen.lock", testutils.ReadTestFile(t, "v200-gen.lock"))
+
+	cfg, err := Load(dir, WithLanguages("go"))
+	require.NoError(t, err)
+
+	gen := cfg.Config.Generation
+	assert.False(t, gen.Fixes.SharedErrorComponentsApr2025, "loading doesn't modify the config")
+	assert.True(t, gen.FixEnabled("sharedErrorComponentsApr2025"))
+	assert.False(t, gen.FixEnabled("securityFeb2025"))
+	assert.False(t, gen.FixEnabled("sharedNestedComponentsJan2026"))
+
+	require.NoError(t, SaveConfig(dir, cfg.Config))
+	data, err := os.ReadFile(filepath.Join(speakeasyDir, "gen.yaml"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "sharedErrorComponentsApr2025: true")
+
+	// Fixes written as false don't opt out when the config is loaded again
+	cfg, err = Load(dir, WithLanguages("go"))
+	require.NoError(t, err)
+	gen = cfg.Config.Generation
+	assert.True(t, gen.FixEnabled("sharedErrorComponentsApr2025"))
+	assert.True(t, gen.FixEnabled("requestResponseComponentNamesFeb2024"))
+	assert.False(t, gen.FixEnabled("securityFeb2025"))
+
+	testutils.CreateTempFile(t, speakeasyDir, "gen.yaml", `configVersion: 2.0.0
+generation:
+  fixesBaseline: 2025-04
+  fixesOptOut:
+    - securityJan2025
+`)
+
+	_, err = Load(dir, WithLanguages("go"))
+	assert.ErrorIs(t, err, ErrInvalidValue)
+
+	testutils.CreateTempFile(t, speakeasyDir, "gen.yaml", `configVersion: 2.0.0
+generation:
+  fixesBaseline: latest
+`)
+
+	_, err = Load(dir, WithLanguages("go"))
+	assert.ErrorIs(t, err, ErrInvalidValue)
+}
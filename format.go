@@ -0,0 +1,280 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/speakeasy-api/sdk-gen-config/lint"
+	"github.com/speakeasy-api/sdk-gen-config/lockfile"
+	"github.com/speakeasy-api/sdk-gen-config/workflow"
+	"github.com/speakeasy-api/sdk-gen-config/workspace"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrNotFormatted      = errors.New("file is not formatted")
+	ErrUnknownFileFormat = errors.New("unknown file format")
+)
+
+// KeyOrder controls the order of keys when gen.yaml and gen.lock are written.
+type KeyOrder string
+
+const (
+	// KeyOrderOriginal keeps existing keys where they are in the file, new keys are added after them with known
+	// fields in declaration order and other keys in alphabetical order
+	KeyOrderOriginal KeyOrder = "original"
+	// KeyOrderSorted writes keys in the canonical order used by Format
+	KeyOrderSorted KeyOrder = "sorted"
+)
+
+// WithKeyOrder sets the order of keys when writing gen.yaml and gen.lock, defaults to KeyOrderOriginal.
+// Either order is deterministic, so writing the same config always produces the same bytes.
+func WithKeyOrder(order KeyOrder) Option {
+	return func(o *options) {
+		o.keyOrder = order
+	}
+}
+
+// formatTypes maps the version key at the root of each supported file to the type describing it.
+var formatTypes = []struct {
+	versionKey string
+	name       string
+	t          reflect.Type
+}{
+	{versionKey: "configVersion", name: configFile, t: reflect.TypeOf(Configuration{})},
+	{versionKey: "lockVersion", name: lockFile, t: reflect.TypeOf(lockfile.LockFile{})},
+	{versionKey: "workflowVersion", name: "workflow.yaml", t: reflect.TypeOf(workflow.Workflow{})},
+	{versionKey: "lintVersion", name: "lint.yaml", t: reflect.TypeOf(lint.Lint{})},
+}
+
+// Format rewrites a gen.yaml, gen.lock, workflow.yaml or lint.yaml document with a canonical key ordering.
+// The type of document is detected from its version key, for example configVersion or lockVersion.
+//
+// Keys known to the Go types describing the document are ordered as the fields are declared, followed by any
+// other keys, such as the languages in gen.yaml, in alphabetical order. Keys of maps, including the sources and
+// targets of workflow.yaml and the trackedFiles of gen.lock, are ordered alphabetically. The order of sequences
+// is left unchanged. Comments and blank lines are preserved and the document is indented by 2 spaces.
+func Format(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("could not parse document: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%w: document must be a mapping", ErrUnknownFileFormat)
+	}
+
+	t, err := detectFormatType(doc.Content[0])
+	if err != nil {
+		return nil, err
+	}
+
+	blankLines := blankLinePaths(data, &doc)
+
+	orderNode(doc.Content[0], t)
+
+	formatted, err := encodeYAML(&doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return restoreBlankLines(formatted, blankLines)
+}
+
+// CheckFormat returns ErrNotFormatted if data differs from the result of Format.
+func CheckFormat(data []byte) error {
+	formatted, err := Format(data)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(data, formatted) {
+		return ErrNotFormatted
+	}
+	return nil
+}
+
+// FormatFiles formats each of the files at paths in place and returns the paths that changed. In check mode
+// nothing is written and ErrNotFormatted is returned, listing the files that need formatting, for use in CI.
+func FormatFiles(paths []string, check bool, opts ...Option) ([]string, error) {
+	o := applyOptions(opts)
+
+	var changed []string
+	for _, path := range paths {
+		data, err := readFile(path, o)
+		if err != nil {
+			return nil, fmt.Errorf("could not read %s: %w", path, err)
+		}
+
+		formatted, err := Format(data)
+		if err != nil {
+			return nil, fmt.Errorf("could not format %s: %w", path, err)
+		}
+
+		if bytes.Equal(data, formatted) {
+			continue
+		}
+		changed = append(changed, path)
+
+		if check {
+			continue
+		}
+
+		writeFileFunc := workspace.WriteFileAtomic
+		if o.FS != nil {
+			writeFileFunc = o.FS.WriteFile
+		}
+		if err := writeFileFunc(path, formatted, 0o666); err != nil {
+			return nil, fmt.Errorf("could not write %s: %w", filepath.Base(path), err)
+		}
+	}
+
+	if check && len(changed) > 0 {
+		return changed, fmt.Errorf("%w: %s", ErrNotFormatted, strings.Join(changed, ", "))
+	}
+
+	return changed, nil
+}
+
+func detectFormatType(root *yaml.Node) (reflect.Type, error) {
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		for _, ft := range formatTypes {
+			if root.Content[i].Value == ft.versionKey {
+				return ft.t, nil
+			}
+		}
+	}
+
+	kinds := make([]string, 0, len(formatTypes))
+	for _, ft := range formatTypes {
+		kinds = append(kinds, fmt.Sprintf("%s (%s)", ft.name, ft.versionKey))
+	}
+	return nil, fmt.Errorf("%w: expected the version key of a %s at the root of the document", ErrUnknownFileFormat, strings.Join(kinds, ", "))
+}
+
+// orderNode sorts the keys of the mappings in n according to the type t describing it, which may be nil for untyped values.
+func orderNode(n *yaml.Node, t reflect.Type) {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch n.Kind {
+	case yaml.SequenceNode:
+		var elem reflect.Type
+		if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			elem = t.Elem()
+		}
+		for _, c := range n.Content {
+			orderNode(c, elem)
+		}
+	case yaml.MappingNode:
+		orderMapping(n, t)
+	}
+}
+
+func orderMapping(n *yaml.Node, t reflect.Type) {
+	var fields []string
+	fieldTypes := map[string]reflect.Type{}
+	var rest reflect.Type
+
+	switch {
+	case t == nil:
+	case t.Kind() == reflect.Struct:
+		fields, fieldTypes, rest = structKeyOrder(t)
+
+		// Ordered maps such as sequencedmap.Map don't have any fields of their own
+		if get, ok := reflect.PointerTo(t).MethodByName("Get"); ok && len(fields) == 0 && rest == nil && get.Type.NumOut() > 0 {
+			rest = get.Type.Out(0)
+		}
+	case t.Kind() == reflect.Map:
+		rest = t.Elem()
+	}
+
+	type pair struct {
+		key, value *yaml.Node
+	}
+	pairs := make([]pair, 0, len(n.Content)/2)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		pairs = append(pairs, pair{key: n.Content[i], value: n.Content[i+1]})
+	}
+
+	rank := func(p pair) int {
+		if isMergeKey(p.key) {
+			return -1
+		}
+		if i := slices.Index(fields, p.key.Value); i >= 0 {
+			return i
+		}
+		return len(fields)
+	}
+
+	slices.SortStableFunc(pairs, func(a, b pair) int {
+		ra, rb := rank(a), rank(b)
+		if ra != rb {
+			return ra - rb
+		}
+		if ra == len(fields) {
+			return strings.Compare(a.key.Value, b.key.Value)
+		}
+		return 0
+	})
+
+	n.Content = n.Content[:0]
+	for _, p := range pairs {
+		n.Content = append(n.Content, p.key, p.value)
+
+		valueType, ok := fieldTypes[p.key.Value]
+		if !ok {
+			valueType = rest
+		}
+		orderNode(p.value, valueType)
+	}
+}
+
+// structKeyOrder returns the yaml keys of t in the order they are declared, along with their types and the element type
+// of any inline map holding the remaining keys.
+func structKeyOrder(t reflect.Type) ([]string, map[string]reflect.Type, reflect.Type) {
+	var keys []string
+	types := map[string]reflect.Type{}
+	var rest reflect.Type
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, inline := yamlFieldName(f)
+		if inline {
+			ft := f.Type
+			for ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			switch ft.Kind() {
+			case reflect.Struct:
+				inlineKeys, inlineTypes, inlineRest := structKeyOrder(ft)
+				keys = append(keys, inlineKeys...)
+				for k, v := range inlineTypes {
+					types[k] = v
+				}
+				if inlineRest != nil {
+					rest = inlineRest
+				}
+			case reflect.Map:
+				rest = ft.Elem()
+			}
+			continue
+		}
+		if name == "" {
+			continue
+		}
+
+		keys = append(keys, name)
+		types[name] = f.Type
+	}
+
+	return keys, types, rest
+}
@@ -0,0 +1,279 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/speakeasy-api/sdk-gen-config/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "gen.yaml orders known keys by declaration and the rest alphabetically",
+			in: `typescript:
+  version: 0.1.0
+  packageName: sdk
+go:
+  # the module path
+  packageName: openapi # trailing comment
+  version: 1.0.0
+generation:
+  unknownOption: true
+  fixes:
+    securityFeb2025: true
+    nameResolutionDec2023: true
+  sdkClassName: SDK
+  baseServerUrl: https://example.com
+
+# Top level comment
+configVersion: 2.0.0
+`,
+			want: `# Top level comment
+configVersion: 2.0.0
+generation:
+  baseServerUrl: https://example.com
+  sdkClassName: SDK
+  fixes:
+    nameResolutionDec2023: true
+    securityFeb2025: true
+  unknownOption: true
+go:
+  version: 1.0.0
+  # the module path
+  packageName: openapi # trailing comment
+typescript:
+  version: 0.1.0
+  packageName: sdk
+`,
+		},
+		{
+			name: "gen.lock orders tracked files alphabetically",
+			in: `management:
+  releaseVersion: 1.0.0
+  docChecksum: abc
+trackedFiles:
+  src/b.ts:
+    last_write_checksum: sha1:2
+    id: b
+  src/a.ts:
+    id: a
+lockVersion: 2.0.0
+id: 123
+`,
+			want: `lockVersion: 2.0.0
+id: 123
+management:
+  docChecksum: abc
+  releaseVersion: 1.0.0
+trackedFiles:
+  src/a.ts:
+    id: a
+  src/b.ts:
+    id: b
+    last_write_checksum: sha1:2
+`,
+		},
+		{
+			name: "workflow.yaml orders sources and targets alphabetically",
+			in: `targets:
+  b-target:
+    source: my-source
+    target: go
+  a-target:
+    target: typescript
+    source: my-source
+sources:
+  my-source:
+    registry:
+      location: registry.speakeasyapi.dev/org/workspace/source
+    inputs:
+      - location: ./b.yaml
+      - location: ./a.yaml
+speakeasyVersion: latest
+workflowVersion: 1.0.0
+`,
+			want: `workflowVersion: 1.0.0
+speakeasyVersion: latest
+sources:
+  my-source:
+    inputs:
+      - location: ./b.yaml
+      - location: ./a.yaml
+    registry:
+      location: registry.speakeasyapi.dev/org/workspace/source
+targets:
+  a-target:
+    target: typescript
+    source: my-source
+  b-target:
+    target: go
+    source: my-source
+`,
+		},
+		{
+			name: "lint.yaml",
+			in: `rulesets:
+  custom:
+    rules:
+      - severity: warn
+        id: rule-a
+    rulesets:
+      - recommended
+defaultRuleset: custom
+lintVersion: 1.0.0
+`,
+			want: `lintVersion: 1.0.0
+defaultRuleset: custom
+rulesets:
+  custom:
+    rulesets:
+      - recommended
+    rules:
+      - id: rule-a
+        severity: warn
+`,
+		},
+		{
+			name:    "unknown document",
+			in:      "foo: bar\n",
+			wantErr: ErrUnknownFileFormat,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Format([]byte(tt.in))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+
+			// Formatting is idempotent
+			assert.NoError(t, CheckFormat(got))
+			if tt.in != tt.want {
+				assert.ErrorIs(t, CheckFormat([]byte(tt.in)), ErrNotFormatted)
+			}
+		})
+	}
+}
+
+func TestFormatFiles(t *testing.T) {
+	dir := t.TempDir()
+	formatted := testutils.ReadTestFile(t, "v200-gen.lock")
+	unformatted := "id: 0f8fad5b-d9cb-469f-a165-70867728950e\nlockVersion: 2.0.0\n"
+
+	testutils.CreateTempFile(t, dir, "gen.lock", formatted)
+	testutils.CreateTempFile(t, dir, "gen.yaml", "generation:\n  sdkClassName: SDK\nconfigVersion: 2.0.0\n")
+	testutils.CreateTempFile(t, dir, "other.lock", unformatted)
+
+	paths := []string{
+		filepath.Join(dir, "gen.lock"),
+		filepath.Join(dir, "gen.yaml"),
+		filepath.Join(dir, "other.lock"),
+	}
+
+	changed, err := FormatFiles(paths, true)
+	assert.ErrorIs(t, err, ErrNotFormatted)
+	assert.Equal(t, paths[1:], changed)
+
+	data, err := os.ReadFile(paths[1])
+	require.NoError(t, err)
+	assert.Equal(t, "generation:\n  sdkClassName: SDK\nconfigVersion: 2.0.0\n", string(data))
+
+	changed, err = FormatFiles(paths, false)
+	require.NoError(t, err)
+	assert.Equal(t, paths[1:], changed)
+
+	data, err = os.ReadFile(paths[1])
+	require.NoError(t, err)
+	assert.Equal(t, "configVersion: 2.0.0\ngeneration:\n  sdkClassName: SDK\n", string(data))
+
+	changed, err = FormatFiles(paths, true)
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+}
+
+func TestLoad_DeterministicWrites(t *testing.T) {
+	genYaml := `configVersion: 2.0.0
+generation:
+  sdkClassName: speakeasy
+  zUnknown: true
+  aUnknown: true
+typescript:
+  version: 0.1.0
+  packageName: sdk
+go:
+  version: 1.0.0
+  packageName: openapi
+python:
+  version: 2.0.0
+  packageName: openapi
+`
+
+	// Adds new keys to the maps on every load so their order depends on how they are encoded
+	transformer := func(cfg *Config) (*Config, error) {
+		for _, lang := range []string{"go", "python", "typescript", "java", "csharp"} {
+			langCfg := cfg.Config.Languages[lang]
+			if langCfg.Cfg == nil {
+				langCfg.Cfg = map[string]any{}
+			}
+			langCfg.Version = "1.0.0"
+			for _, k := range []string{"option1", "option2", "option3", "option4", "option5"} {
+				langCfg.Cfg[k] = lang + "-" + k
+			}
+			cfg.Config.Languages[lang] = langCfg
+		}
+		for _, k := range []string{"new1", "new2", "new3", "new4", "new5"} {
+			cfg.Config.Generation.AdditionalProperties[k] = k
+		}
+		return cfg, nil
+	}
+
+	tests := []struct {
+		name     string
+		keyOrder KeyOrder
+	}{
+		{name: "original order", keyOrder: KeyOrderOriginal},
+		{name: "sorted order", keyOrder: KeyOrderSorted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var want []byte
+			for i := 0; i < 50; i++ {
+				dir := t.TempDir()
+				speakeasyDir := filepath.Join(dir, ".speakeasy")
+				testutils.CreateTempFile(t, speakeasyDir, "gen.yaml", genYaml)
+				testutils.CreateTempFile(t, speakeasyDir, "gen.lock", testutils.ReadTestFile(t, "v200-gen.lock"))
+
+				_, err := Load(dir, WithUpgradeFunc(testUpdateLang), WithTransformerFunc(transformer), WithKeyOrder(tt.keyOrder))
+				require.NoError(t, err)
+
+				data, err := os.ReadFile(filepath.Join(speakeasyDir, "gen.yaml"))
+				require.NoError(t, err)
+
+				if want == nil {
+					want = data
+					continue
+				}
+				require.Equal(t, string(want), string(data), "write %d differs from the first", i)
+			}
+
+			if tt.keyOrder == KeyOrderSorted {
+				assert.NoError(t, CheckFormat(want))
+			} else {
+				assert.Less(t, bytes.Index(want, []byte("\ntypescript:")), bytes.Index(want, []byte("\ngo:")), "existing languages keep their position")
+			}
+		})
+	}
+}
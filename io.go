@@ -1,8 +1,6 @@
 package config
 
 import (
-	"bufio"
-	"bytes"
 	"crypto/md5"
 	"encoding/hex"
 	"errors"
@@ -10,6 +8,7 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/speakeasy-api/sdk-gen-config/lockfile"
 	"github.com/speakeasy-api/sdk-gen-config/workspace"
@@ -25,6 +24,9 @@ type Config struct {
 	Config     *Configuration
 	ConfigPath string
 	LockFile   *LockFile
+	Warnings   []Warning
+
+	provenance map[string][]ValueSource
 }
 
 type FS interface {
@@ -49,6 +51,19 @@ type options struct {
 	transformerFunc        TransformerFunc
 	validateFunc           ValidateFunc
 	dontWrite              bool
+	schemaValidation       bool
+	migrations             *MigrationRegistry
+	targetVersion          string
+	recorder               *writeRecorder
+	checksumAlgorithm      ChecksumAlgorithm
+	provenance             bool
+	deprecatedFields       []SDKGenConfigField
+	rewriteDeprecated      bool
+	prereleaseIdentifier   string
+	buildMetadata          string
+	keyOrder               KeyOrder
+	lock                   bool
+	lockTimeout            time.Duration
 }
 
 func WithFileSystem(fs FS) Option {
@@ -63,6 +78,23 @@ func WithDontWrite() Option {
 	}
 }
 
+// WithLock holds an advisory lock on the .speakeasy directory while Load, SaveConfig and SaveLockFile read and write
+// gen.yaml and gen.lock, waiting up to timeout for other processes to release it. If the lock can't be acquired the
+// error wraps workspace.ErrLocked and names the process holding it. Locks are only taken when using the OS filesystem.
+func WithLock(timeout time.Duration) Option {
+	return func(o *options) {
+		o.lock = true
+		o.lockTimeout = timeout
+	}
+}
+
+// withoutLock stops Load, SaveConfig and SaveLockFile from locking, for callers already holding the lock.
+func withoutLock() Option {
+	return func(o *options) {
+		o.lock = false
+	}
+}
+
 func WithUpgradeFunc(f UpgradeFunc) Option {
 	return func(o *options) {
 		o.UpgradeFunc = f
@@ -93,6 +125,30 @@ func WithValidateFunc(f ValidateFunc) Option {
 	}
 }
 
+// WithSchemaValidation validates gen.yaml, gen.local.yaml and any configs it extends against the embedded JSON schemas
+// while loading. Any violations are returned as a *SchemaValidationError. The schemas only describe the current
+// configVersion, so configs at an older version that aren't upgraded with WithUpgradeFunc aren't validated.
+func WithSchemaValidation() Option {
+	return func(o *options) {
+		o.schemaValidation = true
+	}
+}
+
+// WithMigrationRegistry sets the registry used to plan upgrades, defaults to DefaultMigrations.
+func WithMigrationRegistry(r *MigrationRegistry) Option {
+	return func(o *options) {
+		o.migrations = r
+	}
+}
+
+// WithTargetVersion sets the configVersion that configs are upgraded to, defaults to Version.
+// A path to the version must exist in the migration registry.
+func WithTargetVersion(version string) Option {
+	return func(o *options) {
+		o.targetVersion = version
+	}
+}
+
 func FindConfigFile(dir string, fileSystem FS) (*workspace.FindWorkspaceResult, error) {
 	configRes, err := workspace.FindWorkspace(dir, workspace.FindWorkspaceOptions{
 		FindFile:     configFile,
@@ -125,6 +181,20 @@ func Load(dir string, opts ...Option) (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	if o.lock {
+		release, err := acquireLock(configRes.Path, o)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		// Read the config again in case another process wrote it while we waited for the lock
+		configRes, err = FindConfigFile(dir, o.FS)
+		if err != nil {
+			return nil, err
+		}
+	}
+	originalData := configRes.Data
 	if configRes.Data == nil {
 		newConfig = true
 		newSDK = true
@@ -155,6 +225,9 @@ func Load(dir string, opts ...Option) (*Config, error) {
 		newLockFile = true
 	}
 
+	var rewritten []string
+	// Only the current version of the config is described by the schema
+	validateSchema := o.schemaValidation
 	if !newConfig {
 		// Unmarshal config file and check version
 		cfgMap := map[string]any{}
@@ -182,25 +255,45 @@ func Load(dir string, opts ...Option) (*Config, error) {
 		}
 
 		// If we aren't upgrading we assume if we are missing a lock file then this is a new SDK
-		if version == Version {
+		if version == o.targetVersion {
 			newSDK = newSDK || newLockFile
 		}
 
-		if version != Version && o.UpgradeFunc != nil {
+		currentVersion := version
+		if version != o.targetVersion && o.UpgradeFunc != nil {
 			// Upgrade config file if version is different and write it
-			cfgMap, lockFileMap, err = upgrade(version, cfgMap, lockFileMap, o.UpgradeFunc)
+			cfgMap, lockFileMap, err = o.migrations.Migrate(version, o.targetVersion, cfgMap, lockFileMap, o.UpgradeFunc)
 			if err != nil {
 				return nil, err
 			}
+			currentVersion = o.targetVersion
 
 			// Write back out to disk and update data
-			configRes.Data, err = write(configRes.Path, cfgMap, o)
+			configRes.Data, err = write(configRes.Path, cfgMap, configRes.Data, o)
 			if err != nil {
 				return nil, err
 			}
 
 			if lockFileMap != nil {
-				lockFileRes.Data, err = write(lockFileRes.Path, lockFileMap, o)
+				lockFileRes.Data, err = write(lockFileRes.Path, lockFileMap, lockFileRes.Data, o)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			if o.recorder != nil {
+				o.recorder.markMigrated()
+			}
+		}
+
+		if currentVersion != Version {
+			validateSchema = false
+		}
+
+		if o.rewriteDeprecated {
+			rewritten = rewriteDeprecated(cfgMap, o.deprecations())
+			if len(rewritten) > 0 {
+				configRes.Data, err = write(configRes.Path, cfgMap, configRes.Data, o)
 				if err != nil {
 					return nil, err
 				}
@@ -244,7 +337,7 @@ func Load(dir string, opts ...Option) (*Config, error) {
 	// If this is a totally new config, we need to write out to disk for following operations
 	if newConfig && o.UpgradeFunc != nil {
 		// Write new cfg
-		configRes.Data, err = write(configRes.Path, cfg, o)
+		configRes.Data, err = write(configRes.Path, cfg, nil, o)
 		if err != nil {
 			return nil, err
 		}
@@ -252,17 +345,46 @@ func Load(dir string, opts ...Option) (*Config, error) {
 
 	if lockFileRes.Data == nil && o.UpgradeFunc != nil {
 		lockFile := NewLockFile()
-		lockFileRes.Data, err = write(lockFileRes.Path, lockFile, o)
+		lockFileRes.Data, err = write(lockFileRes.Path, lockFile, nil, o)
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	// Merge in any extended base configs and gen.local.yaml
+	layers, err := loadConfigLayers(configRes.Path, configRes.Data, o)
+	if err != nil {
+		return nil, err
+	}
+
+	if validateSchema {
+		if err := layers.validateSchema(); err != nil {
+			return nil, err
+		}
+	}
+
+	cfgData := configRes.Data
+	if layers.layered() {
+		cfgData, err = yaml.Marshal(layers.merged())
+		if err != nil {
+			return nil, fmt.Errorf("could not merge gen.yaml: %w", err)
+		}
+	}
+
 	// Okay finally able to unmarshal the config file into expected struct
-	if err := yaml.Unmarshal(configRes.Data, cfg); err != nil {
+	if err := yaml.Unmarshal(cfgData, cfg); err != nil {
 		return nil, fmt.Errorf("could not unmarshal gen.yaml: %w", err)
 	}
 
+	if cfg.Generation.FixesBaseline != "" {
+		if _, err := parseFixesBaseline(cfg.Generation.FixesBaseline); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateFixesOptOut(cfg.Generation.FixesOptOut); err != nil {
+		return nil, err
+	}
+
 	var lockOpts []lockfile.LoadOption
 	if o.FS != nil {
 		lockOpts = append(lockOpts, lockfile.WithFileSystem(o.FS))
@@ -308,6 +430,25 @@ func Load(dir string, opts ...Option) (*Config, error) {
 		LockFile:   lock,
 	}
 
+	// Keys dropped by migrations are reported from the config as it was before upgrading
+	warningLayers := layers.all()
+	if originalData != nil {
+		original, err := newConfigLayer(SourceFile, configRes.Path, originalData)
+		if err != nil {
+			return nil, err
+		}
+		warningLayers = append([]*configLayer{original}, warningLayers...)
+	}
+	config.Warnings = collectWarnings(warningLayers, o.deprecations(), rewritten, cfg)
+
+	var beforeTransform map[string]any
+	if o.provenance {
+		beforeTransform, err = flattenConfig(config.Config)
+		if err != nil {
+			return nil, fmt.Errorf("could not record provenance: %w", err)
+		}
+	}
+
 	if o.transformerFunc != nil {
 		config, err = o.transformerFunc(config)
 		if err != nil {
@@ -315,12 +456,19 @@ func Load(dir string, opts ...Option) (*Config, error) {
 		}
 	}
 
+	if o.provenance {
+		config.provenance, err = resolveProvenance(layers, defaultCfg, beforeTransform, config.Config)
+		if err != nil {
+			return nil, fmt.Errorf("could not record provenance: %w", err)
+		}
+	}
+
 	if o.UpgradeFunc != nil {
 		// Finally write out the files to solidfy any defaults, upgrades or transformations
-		if _, err := write(configRes.Path, config.Config, o); err != nil {
+		if _, err := writeConfig(configRes.Path, config.Config, configRes.Data, layers, o); err != nil {
 			return nil, err
 		}
-		if _, err := write(lockFileRes.Path, config.LockFile, o); err != nil {
+		if _, err := write(lockFileRes.Path, config.LockFile, lockFileRes.Data, o); err != nil {
 			return nil, err
 		}
 	}
@@ -364,9 +512,16 @@ func GetTemplateVersion(dir, target string, opts ...Option) (string, error) {
 		return "", nil
 	}
 
-	return tv.(string), nil
+	templateVersion, ok := tv.(string)
+	if !ok {
+		return "", fmt.Errorf("templateVersion for %s must be a string, got %T", target, tv)
+	}
+
+	return templateVersion, nil
 }
 
+// SaveConfig writes cfg to gen.yaml. Values that match those provided by gen.local.yaml or the base configs
+// listed in extends are not written, so the committed file keeps its own values for them.
 func SaveConfig(dir string, cfg *Configuration, opts ...Option) error {
 	o := applyOptions(opts)
 
@@ -374,8 +529,25 @@ func SaveConfig(dir string, cfg *Configuration, opts ...Option) error {
 	if err != nil {
 		return err
 	}
+	if o.lock {
+		release, err := acquireLock(configRes.Path, o)
+		if err != nil {
+			return err
+		}
+		defer release()
+
+		configRes, err = FindConfigFile(dir, o.FS)
+		if err != nil {
+			return err
+		}
+	}
+
+	layers, err := loadConfigLayers(configRes.Path, configRes.Data, o)
+	if err != nil {
+		return err
+	}
 
-	if _, err := write(configRes.Path, cfg, o); err != nil {
+	if _, err := writeConfig(configRes.Path, cfg, configRes.Data, layers, o); err != nil {
 		return err
 	}
 
@@ -385,26 +557,49 @@ func SaveConfig(dir string, cfg *Configuration, opts ...Option) error {
 func SaveLockFile(dir string, lf *LockFile, opts ...Option) error {
 	o := applyOptions(opts)
 
+	lockFileRes, err := findLockFile(dir, o)
+	if err != nil {
+		return err
+	}
+	if o.lock {
+		release, err := acquireLock(lockFileRes.Path, o)
+		if err != nil {
+			return err
+		}
+		defer release()
+
+		lockFileRes, err = findLockFile(dir, o)
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err := write(lockFileRes.Path, lf, lockFileRes.Data, o); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func findLockFile(dir string, o *options) (*workspace.FindWorkspaceResult, error) {
 	lockFileRes, err := workspace.FindWorkspace(dir, workspace.FindWorkspaceOptions{
 		FindFile: lockFile,
 		FS:       o.FS,
 	})
 	if err != nil {
 		if !errors.Is(err, fs.ErrNotExist) {
-			return err
+			return nil, err
 		}
 		lockFileRes = &workspace.FindWorkspaceResult{
 			Path: filepath.Join(dir, workspace.SpeakeasyFolder, lockFile),
 		}
 	}
 
-	if _, err := write(lockFileRes.Path, lf, o); err != nil {
-		return err
-	}
-
-	return nil
+	return lockFileRes, nil
 }
 
+// GetConfigChecksum returns an MD5 of the raw gen.yaml bytes. Any change to the file, including formatting
+// and comments, changes the checksum. See GetCanonicalConfigChecksum for a checksum of the parsed config.
 func GetConfigChecksum(dir string, opts ...Option) (string, error) {
 	o := applyOptions(opts)
 
@@ -420,33 +615,49 @@ func GetConfigChecksum(dir string, opts ...Option) (string, error) {
 	return hex.EncodeToString(hash[:]), nil
 }
 
-func write(path string, cfg any, o *options) ([]byte, error) {
-	var b bytes.Buffer
-	buf := bufio.NewWriter(&b)
+func readFile(path string, o *options) ([]byte, error) {
+	if o.FS != nil {
+		return o.FS.ReadFile(path)
+	}
+	return os.ReadFile(path)
+}
 
-	e := yaml.NewEncoder(buf)
-	e.SetIndent(2)
-	if err := e.Encode(cfg); err != nil {
+// acquireLock locks the workspace directory holding path, returning a function to release it.
+func acquireLock(path string, o *options) (func(), error) {
+	wo := &workspace.Options{FS: o.FS, Locking: true, LockTimeout: o.lockTimeout}
+	return wo.AcquireLock(filepath.Dir(path))
+}
+
+// write marshals cfg to path. If original holds the current contents of the file
+// the new values are patched into it so that comments and formatting are kept.
+func write(path string, cfg any, original []byte, o *options) ([]byte, error) {
+	data, err := patchYAML(original, cfg)
+	if err != nil {
 		return nil, fmt.Errorf("could not marshal %s: %w", path, err)
 	}
 
-	if err := buf.Flush(); err != nil {
-		return nil, fmt.Errorf("could not marshal %s: %w", path, err)
+	if o.keyOrder == KeyOrderSorted {
+		data, err = Format(data)
+		if err != nil {
+			return nil, fmt.Errorf("could not format %s: %w", path, err)
+		}
 	}
 
-	data := b.Bytes()
+	if o.recorder != nil {
+		o.recorder.record(path, original, data)
+	}
 
 	if o.dontWrite {
 		return data, nil
 	}
 
-	writeFileFunc := os.WriteFile
+	writeFileFunc := workspace.WriteFileAtomic
 	if o.FS != nil {
 		writeFileFunc = o.FS.WriteFile
 	}
 
 	if err := writeFileFunc(path, data, 0o666); err != nil {
-		return nil, fmt.Errorf("could not write gen.yaml: %w", err)
+		return nil, fmt.Errorf("could not write %s: %w", filepath.Base(path), err)
 	}
 
 	return data, nil
@@ -454,8 +665,12 @@ func write(path string, cfg any, o *options) ([]byte, error) {
 
 func applyOptions(opts []Option) *options {
 	o := &options{
-		FS:    nil,
-		langs: []string{},
+		FS:                nil,
+		langs:             []string{},
+		migrations:        DefaultMigrations,
+		targetVersion:     Version,
+		checksumAlgorithm: ChecksumSHA256,
+		keyOrder:          KeyOrderOriginal,
 	}
 	for _, opt := range opts {
 		opt(o)
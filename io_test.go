@@ -1,16 +1,19 @@
 package config
 
 import (
+	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/speakeasy-api/openapi/sequencedmap"
 	"github.com/speakeasy-api/sdk-gen-config/lockfile"
 	"github.com/speakeasy-api/sdk-gen-config/testutils"
 	"github.com/speakeasy-api/sdk-gen-config/workspace"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const testDir = "gen/test"
@@ -210,6 +213,7 @@ func TestLoad_Success(t *testing.T) {
 					New: map[string]bool{},
 				},
 				ConfigPath: filepath.Join(os.TempDir(), testDir, ".speakeasy/gen.yaml"),
+				Warnings:   v100Warnings(filepath.Join(os.TempDir(), testDir, ".speakeasy/gen.yaml")),
 				LockFile: &LockFile{
 					LockVersion: lockfile.LockV2,
 					ID:          "123",
@@ -556,6 +560,7 @@ func TestLoad_Success(t *testing.T) {
 					},
 				},
 				ConfigPath: filepath.Join(os.TempDir(), testDir, "gen.yaml"),
+				Warnings:   v100Warnings(filepath.Join(os.TempDir(), testDir, "gen.yaml")),
 				LockFile: &LockFile{
 					LockVersion: lockfile.LockV2,
 					ID:          "123",
@@ -822,6 +827,7 @@ func TestLoad_BackwardsCompatibility_Success(t *testing.T) {
 			New: map[string]bool{},
 		},
 		ConfigPath: filepath.Join(os.TempDir(), testDir, "gen.yaml"),
+		Warnings:   v100Warnings(filepath.Join(os.TempDir(), testDir, "gen.yaml")),
 		LockFile: &LockFile{
 			LockVersion: lockfile.LockV2,
 			ID:          "123",
@@ -972,3 +978,57 @@ management: {}
 		})
 	}
 }
+
+// v100Warnings returns the warnings for the keys in testdata/v100-gen.yaml that were removed in v2.0.0.
+func v100Warnings(path string) []Warning {
+	deprecation := &Deprecation{Since: v1, RemovedIn: v2}
+	return []Warning{
+		{
+			Kind:        WarningDeprecatedKey,
+			Path:        "generation.comments",
+			Source:      ValueSource{Layer: SourceFile, File: path, Line: 4, Column: 3},
+			Message:     "generation.comments is deprecated since 1.0.0 and is removed in 2.0.0",
+			Deprecation: deprecation,
+		},
+		{
+			Kind:        WarningDeprecatedKey,
+			Path:        "generation.tagNamespacingDisabled",
+			Source:      ValueSource{Layer: SourceFile, File: path, Line: 8, Column: 3},
+			Message:     "generation.tagNamespacingDisabled is deprecated since 1.0.0 and is removed in 2.0.0",
+			Deprecation: deprecation,
+		},
+	}
+}
+
+func TestLoad_WithLock(t *testing.T) {
+	getUUID = func() string {
+		return "123"
+	}
+	lockfile.GetUUID = getUUID
+
+	dir := t.TempDir()
+	speakeasyDir := filepath.Join(dir, workspace.SpeakeasyFolder)
+	testutils.CreateTempFile(t, speakeasyDir, "gen.yaml", testutils.ReadTestFile(t, "v200-gen.yaml"))
+	testutils.CreateTempFile(t, speakeasyDir, "gen.lock", testutils.ReadTestFile(t, "v200-gen.lock"))
+
+	held, err := workspace.AcquireLock(speakeasyDir, 0)
+	require.NoError(t, err)
+
+	_, err = Load(dir, WithUpgradeFunc(testUpdateLang), WithLock(100*time.Millisecond))
+	assert.ErrorIs(t, err, workspace.ErrLocked)
+	assert.ErrorContains(t, err, fmt.Sprintf("held by process %d", os.Getpid()))
+
+	err = SaveLockFile(dir, &LockFile{LockVersion: "2.0.0"}, WithLock(100*time.Millisecond))
+	assert.ErrorIs(t, err, workspace.ErrLocked)
+
+	require.NoError(t, held.Release())
+
+	cfg, err := Load(dir, WithUpgradeFunc(testUpdateLang), WithLock(time.Second))
+	require.NoError(t, err)
+	require.NoError(t, SaveLockFile(dir, cfg.LockFile, WithLock(time.Second)))
+	require.NoError(t, SaveConfig(dir, cfg.Config, WithLock(time.Second)))
+
+	// The lock is released once each call returns
+	_, err = os.Stat(filepath.Join(speakeasyDir, workspace.LockFileName))
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AsTyped decodes a language config into one of the generated typed language configs, for example
+//
+//	goCfg, err := config.AsTyped[config.GoConfig](cfg.Languages["go"])
+//
+// Keys that aren't described by the language schema are kept in the AdditionalProperties of the typed
+// config so that converting back with FromTyped is lossless.
+func AsTyped[T any](lc LanguageConfig) (*T, error) {
+	data, err := yaml.Marshal(lc)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal language config: %w", err)
+	}
+
+	typed := new(T)
+	if err := yaml.Unmarshal(data, typed); err != nil {
+		return nil, fmt.Errorf("could not convert language config to %T: %w", *typed, err)
+	}
+
+	return typed, nil
+}
+
+// FromTyped converts a typed language config such as GoConfig back into a LanguageConfig.
+func FromTyped[T any](typed *T) (LanguageConfig, error) {
+	if typed == nil {
+		return LanguageConfig{}, nil
+	}
+
+	data, err := yaml.Marshal(typed)
+	if err != nil {
+		return LanguageConfig{}, fmt.Errorf("could not marshal %T: %w", *typed, err)
+	}
+
+	var lc LanguageConfig
+	if err := yaml.Unmarshal(data, &lc); err != nil {
+		return LanguageConfig{}, fmt.Errorf("could not convert %T to language config: %w", *typed, err)
+	}
+
+	return lc, nil
+}
@@ -0,0 +1,339 @@
+// Code generated by tools/lang-gen from schemas/languages. DO NOT EDIT.
+
+package config
+
+// CSharpConfig is the typed configuration for csharp SDKs, generated from schemas/languages/csharp.schema.json.
+type CSharpConfig struct {
+	// The name of the author of the published package. https://learn.microsoft.com/en-us/nuget/create-packages/package-authoring-best-practices#authors
+	Author *string `yaml:"author,omitempty"`
+	// Whether to treat 4xx and 5xx status codes as errors.
+	ClientServerStatusCodesAsErrors *bool `yaml:"clientServerStatusCodesAsErrors,omitempty"`
+	// The name of the default exception that is thrown when an API error occurs.
+	DefaultErrorName *string `yaml:"defaultErrorName,omitempty"`
+	// Whether to disable Pascal Casing sanitization on provided packageName when setting the root namespace and NuGet package ID.
+	DisableNamespacePascalCasingApr2024 *bool `yaml:"disableNamespacePascalCasingApr2024,omitempty"`
+	// The version of .NET to target. net8.0 (default), net6.0 and net5.0 supported. https://learn.microsoft.com/en-us/dotnet/standard/frameworks
+	DotnetVersion *string `yaml:"dotnetVersion,omitempty"`
+	// Whether to produce and publish the package with Source Link. https://github.com/dotnet/sourcelink
+	EnableSourceLink *bool `yaml:"enableSourceLink,omitempty"`
+	// Flatten the global security configuration if there is only a single option in the spec
+	FlattenGlobalSecurity *bool `yaml:"flattenGlobalSecurity,omitempty"`
+	// When flattening parameters and body fields, determines the ordering of generated method arguments. Leave empty to apply legacy ordering.
+	FlatteningOrder *string `yaml:"flatteningOrder,omitempty"`
+	// Whether to generate .pdb files and publish a .snupkg symbol package to NuGet.
+	IncludeDebugSymbols *bool `yaml:"includeDebugSymbols,omitempty"`
+	// The suffix to add to models with writeOnly fields that are created as input models
+	InputModelSuffix *string `yaml:"inputModelSuffix,omitempty"`
+	// The maximum number of parameters a method can have before the resulting SDK endpoint is no longer 'flattened' and an input object is created instead. 0 will use input objects always. https://www.speakeasy.com/docs/customize-sdks/methods
+	MaxMethodParams *float64 `yaml:"maxMethodParams,omitempty"`
+	// Determines how arguments for SDK methods are generated
+	MethodArguments *string `yaml:"methodArguments,omitempty"`
+	// The suffix to add to models with writeOnly fields that are created as input models
+	OutputModelSuffix *string `yaml:"outputModelSuffix,omitempty"`
+	// The NuGet package ID, also used as the root namespace. https://learn.microsoft.com/en-us/dotnet/standard/design-guidelines/names-of-namespaces.
+	PackageName *string `yaml:"packageName,omitempty"`
+	// Space-delimited list of tags and keywords used when searching for packages on NuGet.
+	PackageTags *string `yaml:"packageTags,omitempty"`
+	// Determines the shape of the response envelope that is return from SDK methods
+	ResponseFormat *string `yaml:"responseFormat,omitempty"`
+	// The name of the source directory. Default is "src"
+	SourceDirectory *string `yaml:"sourceDirectory,omitempty"`
+	// The current version of the SDK
+	Version string `yaml:"version"`
+	// Captures any keys that are not described by the schema
+	AdditionalProperties map[string]any `yaml:",inline"`
+}
+
+// GoConfig is the typed configuration for go SDKs, generated from schemas/languages/go.schema.json.
+type GoConfig struct {
+	// Allow unknown fields in weak (undiscriminated) unions
+	AllowUnknownFieldsInWeakUnions *bool `yaml:"allowUnknownFieldsInWeakUnions,omitempty"`
+	// Whether to treat 4xx and 5xx status codes as errors.
+	ClientServerStatusCodesAsErrors *bool `yaml:"clientServerStatusCodesAsErrors,omitempty"`
+	// The name of the default error type used to represent API errors
+	DefaultErrorName *string `yaml:"defaultErrorName,omitempty"`
+	// The environment variable prefix for security and global env variable overrides. If empty these overrides will not be possible
+	EnvVarPrefix any `yaml:"envVarPrefix,omitempty"`
+	// Flatten the global security configuration if there is only a single option in the spec
+	FlattenGlobalSecurity *bool `yaml:"flattenGlobalSecurity,omitempty"`
+	// The suffix to add to models with writeOnly fields that are created as input models
+	InputModelSuffix *string `yaml:"inputModelSuffix,omitempty"`
+	// The maximum number of parameters a method can have before the resulting SDK endpoint is no longer 'flattened' and an input object is created instead. 0 will use input objects always. https://www.speakeasy.com/docs/customize-sdks/methods
+	MaxMethodParams *float64 `yaml:"maxMethodParams,omitempty"`
+	// Determines how arguments for SDK methods are generated
+	MethodArguments *string `yaml:"methodArguments,omitempty"`
+	// The suffix to add to models with writeOnly fields that are created as input models
+	OutputModelSuffix *string `yaml:"outputModelSuffix,omitempty"`
+	// The go module package name. https://go.dev/ref/mod#module-path.
+	PackageName *string `yaml:"packageName,omitempty"`
+	// Determines the shape of the response envelope that is returned from SDK methods
+	ResponseFormat *string `yaml:"responseFormat,omitempty"`
+	// The current version of the SDK
+	Version string `yaml:"version"`
+	// Captures any keys that are not described by the schema
+	AdditionalProperties map[string]any `yaml:",inline"`
+}
+
+// JavaConfig is the typed configuration for java SDKs, generated from schemas/languages/java.schema.json.
+type JavaConfig struct {
+	// The artifactID to use for namespacing the package. This is usually the name of your project. If publishing is enabled, it will also be used as the artifactId (e.g. com.your-org.<artifactId>).
+	ArtifactID *string `yaml:"artifactID,omitempty"`
+	// Whether to treat 4xx and 5xx status codes as errors.
+	ClientServerStatusCodesAsErrors *bool `yaml:"clientServerStatusCodesAsErrors,omitempty"`
+	// A support email address for your company. Sets metadata required by Maven.
+	CompanyEmail *string `yaml:"companyEmail,omitempty"`
+	// The name of your company. Sets metadata required by Maven.
+	CompanyName *string `yaml:"companyName,omitempty"`
+	// Your company's homepage URL. Sets metadata required by Maven.
+	CompanyURL *string `yaml:"companyURL,omitempty"`
+	// The name of the default exception that is thrown when an API error occurs.
+	DefaultErrorName *string `yaml:"defaultErrorName,omitempty"`
+	// Flatten the global security configuration if there is only a single option in the spec
+	FlattenGlobalSecurity *bool `yaml:"flattenGlobalSecurity,omitempty"`
+	// The github URL where the artifact is hosted. Sets metadata required by Maven.
+	GithubURL *string `yaml:"githubURL,omitempty"`
+	// The groupID to use for namespacing the package. This is usually the reversed domain name of your organization. If publishing is enabled, it will also be used as the artifact's groupId (e.g. <groupId>.my-artifact).
+	GroupID *string `yaml:"groupID,omitempty"`
+	// The suffix to add to models with writeOnly fields that are created as input models
+	InputModelSuffix *string `yaml:"inputModelSuffix,omitempty"`
+	// The maximum number of parameters a method can have before the resulting SDK endpoint is no longer 'flattened' and an input object is created instead. 0 will use input objects always. https://www.speakeasy.com/docs/customize-sdks/methods
+	MaxMethodParams *float64 `yaml:"maxMethodParams,omitempty"`
+	// The URL of the staging repository to publish the SDK artifact to.
+	OssrhURL any `yaml:"ossrhURL,omitempty"`
+	// The suffix to add to models with writeOnly fields that are created as input models
+	OutputModelSuffix *string `yaml:"outputModelSuffix,omitempty"`
+	// Assigns Gradle rootProject.name, which gives a name to the Gradle build. https://docs.gradle.org/current/userguide/multi_project_builds.html#naming_recommendations
+	ProjectName *string `yaml:"projectName,omitempty"`
+	// The template version to use
+	TemplateVersion *string `yaml:"templateVersion,omitempty"`
+	// The current version of the SDK
+	Version string `yaml:"version"`
+	// Captures any keys that are not described by the schema
+	AdditionalProperties map[string]any `yaml:",inline"`
+}
+
+// PHPConfig is the typed configuration for php SDKs, generated from schemas/languages/php.schema.json.
+type PHPConfig struct {
+	// Whether to treat 4xx and 5xx status codes as errors.
+	ClientServerStatusCodesAsErrors *bool `yaml:"clientServerStatusCodesAsErrors,omitempty"`
+	// The name of the default exception that is thrown when an API error occurs.
+	DefaultErrorName *string `yaml:"defaultErrorName,omitempty"`
+	// The environment variable prefix for laravel service provider env variable overrides. If empty these overrides will not be prefixed
+	EnvVarPrefix any `yaml:"envVarPrefix,omitempty"`
+	// Flatten the global security configuration if there is only a single option in the spec
+	FlattenGlobalSecurity *bool `yaml:"flattenGlobalSecurity,omitempty"`
+	// The suffix to add to models with writeOnly fields that are created as input models
+	InputModelSuffix *string `yaml:"inputModelSuffix,omitempty"`
+	// The maximum number of parameters a method can have before the resulting SDK endpoint is no longer 'flattened' and an input object is created instead. 0 will use input objects always. https://www.speakeasy.com/docs/customize-sdks/methods
+	MaxMethodParams *float64 `yaml:"maxMethodParams,omitempty"`
+	// Determines how arguments for SDK methods are generated.  PHP only supports `infer-optional-args` - this configuration option is only here for consistency.
+	MethodArguments *string `yaml:"methodArguments,omitempty"`
+	// https://www.php.net/manual/en/language.namespaces.rationale.php
+	Namespace *string `yaml:"namespace,omitempty"`
+	// The suffix to add to models with writeOnly fields that are created as input models
+	OutputModelSuffix *string `yaml:"outputModelSuffix,omitempty"`
+	// The name of the composer package. https://getcomposer.org/doc/04-schema.md#name
+	PackageName *string `yaml:"packageName,omitempty"`
+	// The current version of the SDK
+	Version string `yaml:"version"`
+	// Captures any keys that are not described by the schema
+	AdditionalProperties map[string]any `yaml:",inline"`
+}
+
+// PostmanConfig is the typed configuration for postman SDKs, generated from schemas/languages/postman.schema.json.
+type PostmanConfig struct {
+	// The collection file name. If not file name is provided the packageName is used in the `{example}_postman_collection.json` if no file name is provided.
+	FileName any `yaml:"fileName,omitempty"`
+	// The suffix to add to models with writeOnly fields that are created as input models
+	InputModelSuffix *string `yaml:"inputModelSuffix,omitempty"`
+	// The suffix to add to models with writeOnly fields that are created as input models
+	OutputModelSuffix *string `yaml:"outputModelSuffix,omitempty"`
+	// The name of the Postman collection. This show as the name when imported into Postman. This is also used as the file name in `{example}_postman_collection.json` if no file name is provided.
+	PackageName *string `yaml:"packageName,omitempty"`
+	// Captures any keys that are not described by the schema
+	AdditionalProperties map[string]any `yaml:",inline"`
+}
+
+// PythonConfig is the typed configuration for python SDKs, generated from schemas/languages/python.schema.json.
+type PythonConfig struct {
+	// Whether to treat 4xx and 5xx status codes as errors.
+	ClientServerStatusCodesAsErrors *bool `yaml:"clientServerStatusCodesAsErrors,omitempty"`
+	// The name of the default exception that is raised when an API error occurs.
+	DefaultErrorName *string `yaml:"defaultErrorName,omitempty"`
+	// A short description of the project. https://python-poetry.org/docs/pyproject/#description
+	Description *string `yaml:"description,omitempty"`
+	// The URL for the documentation of the project. https://python-poetry.org/docs/pyproject/#documentation
+	DocumentationURL any `yaml:"documentationUrl,omitempty"`
+	// Allow custom code to be inserted into the generated SDK.
+	EnableCustomCodeRegions *bool `yaml:"enableCustomCodeRegions,omitempty"`
+	// Determines the format to express enums in Python
+	EnumFormat *string `yaml:"enumFormat,omitempty"`
+	// The environment variable prefix for security and global env variable overrides. If empty these overrides will not be possible
+	EnvVarPrefix any `yaml:"envVarPrefix,omitempty"`
+	// Flatten the global security configuration if there is only a single option in the spec
+	FlattenGlobalSecurity *bool `yaml:"flattenGlobalSecurity,omitempty"`
+	// Turn request parameters and body fields into a flat list of method arguments. This takes precedence over maxMethodParams. If there is no request body then maxMethodParams will be respected.
+	FlattenRequests *bool `yaml:"flattenRequests,omitempty"`
+	// When flattening parameters and body fields, determines the ordering of generated method arguments.
+	FlatteningOrder *string `yaml:"flatteningOrder,omitempty"`
+	// The URL for the homepage of the project. https://python-poetry.org/docs/pyproject/#homepage
+	Homepage any `yaml:"homepage,omitempty"`
+	// The suffix to add to models with writeOnly fields that are created as input models
+	InputModelSuffix *string `yaml:"inputModelSuffix,omitempty"`
+	// The maximum number of parameters a method can have before the resulting SDK endpoint is no longer 'flattened' and an input object is created instead. 0 will use input objects always. https://www.speakeasy.com/docs/customize-sdks/methods
+	MaxMethodParams *float64 `yaml:"maxMethodParams,omitempty"`
+	// Determines how arguments for SDK methods are generated
+	MethodArguments *string `yaml:"methodArguments,omitempty"`
+	// The suffix to add to models with writeOnly fields that are created as input models
+	OutputModelSuffix *string `yaml:"outputModelSuffix,omitempty"`
+	// The distribution name of the PyPI Package. https://docs.python.org/3.11/distutils/setupscript.html#additional-meta-data
+	PackageName *string `yaml:"packageName,omitempty"`
+	// Determines the shape of the response envelope that is returned from SDK methods
+	ResponseFormat *string `yaml:"responseFormat,omitempty"`
+	// The template version to use
+	TemplateVersion *string `yaml:"templateVersion,omitempty"`
+	// The current version of the SDK
+	Version string `yaml:"version"`
+	// Captures any keys that are not described by the schema
+	AdditionalProperties map[string]any `yaml:",inline"`
+}
+
+// RubyConfig is the typed configuration for ruby SDKs, generated from schemas/languages/ruby.schema.json.
+type RubyConfig struct {
+	// The name of the author of the published package. https://docs.python.org/3/distutils/setupscript.html#additional-meta-data
+	Author      *string `yaml:"author,omitempty"`
+	Description *string `yaml:"description,omitempty"`
+	// The suffix to add to models with writeOnly fields that are created as input models
+	InputModelSuffix *string `yaml:"inputModelSuffix,omitempty"`
+	// The maximum number of parameters a method can have before the resulting SDK endpoint is no longer 'flattened' and an input object is created instead. 0 will use input objects always. https://www.speakeasy.com/docs/using-speakeasy/create-client-sdks/customize-sdks/parameters/
+	MaxMethodParams *float64 `yaml:"maxMethodParams,omitempty"`
+	// https://ruby-doc.org/core-2.5.3/Module.html
+	Module *string `yaml:"module,omitempty"`
+	// The suffix to add to models with writeOnly fields that are created as input models
+	OutputModelSuffix *string `yaml:"outputModelSuffix,omitempty"`
+	// The distribution name of the PyPI Package. https://docs.python.org/3/distutils/setupscript.html#additional-meta-data
+	PackageName *string `yaml:"packageName,omitempty"`
+	// The current version of the SDK
+	Version string `yaml:"version"`
+	// Captures any keys that are not described by the schema
+	AdditionalProperties map[string]any `yaml:",inline"`
+}
+
+// TerraformConfig is the typed configuration for terraform SDKs, generated from schemas/languages/terraform.schema.json.
+type TerraformConfig struct {
+	// Allow unknown fields in weak (undiscriminated) unions
+	AllowUnknownFieldsInWeakUnions *bool `yaml:"allowUnknownFieldsInWeakUnions,omitempty"`
+	// The name of the author of the published package.
+	Author *string `yaml:"author,omitempty"`
+	// The name of the default error type used to represent API errors
+	DefaultErrorName *string `yaml:"defaultErrorName,omitempty"`
+	// Enables deduplication of terraform value types
+	EnableTypeDeduplication *bool `yaml:"enableTypeDeduplication,omitempty"`
+	// The terraform provider name.
+	PackageName *string `yaml:"packageName,omitempty"`
+	// The current version of the SDK
+	Version string `yaml:"version"`
+	// Captures any keys that are not described by the schema
+	AdditionalProperties map[string]any `yaml:",inline"`
+}
+
+// TypeScriptConfig is the typed configuration for typescript SDKs, generated from schemas/languages/typescript.schema.json.
+type TypeScriptConfig struct {
+	// The name of the author of the published package. https://docs.npmjs.com/cli/v9/configuring-npm/package-json#people-fields-author-contributors
+	Author *string `yaml:"author,omitempty"`
+	// Whether to treat 4xx and 5xx status codes as errors.
+	ClientServerStatusCodesAsErrors *bool `yaml:"clientServerStatusCodesAsErrors,omitempty"`
+	// The command to use for compiling the SDK. This must be an array where the first element is the command and the rest are arguments.
+	CompileCommand any `yaml:"compileCommand,omitempty"`
+	// The name of the default error class used to represent API errors
+	DefaultErrorName *string `yaml:"defaultErrorName,omitempty"`
+	// Allow custom code to be inserted into the generated SDK.
+	EnableCustomCodeRegions *bool `yaml:"enableCustomCodeRegions,omitempty"`
+	// Generate React hooks using TanStack Query.
+	EnableReactQuery *bool `yaml:"enableReactQuery,omitempty"`
+	// Determines the format to express enums in TypeScript
+	EnumFormat *string `yaml:"enumFormat,omitempty"`
+	// The environment variable prefix for security and global env variable overrides. If empty these overrides will not be possible
+	EnvVarPrefix any `yaml:"envVarPrefix,omitempty"`
+	// Flatten the global security configuration if there is only a single option in the spec
+	FlattenGlobalSecurity *bool `yaml:"flattenGlobalSecurity,omitempty"`
+	// When flattening parameters and body fields, determines the ordering of generated method arguments.
+	FlatteningOrder *string `yaml:"flatteningOrder,omitempty"`
+	// The suffix to add to models with writeOnly fields that are created as input models
+	InputModelSuffix *string `yaml:"inputModelSuffix,omitempty"`
+	// The maximum number of parameters a method can have before the resulting SDK endpoint is no longer 'flattened' and an input object is created instead. 0 will use input objects always. https://www.speakeasy.com/docs/customize-sdks/methods
+	MaxMethodParams *float64 `yaml:"maxMethodParams,omitempty"`
+	// Determines how arguments for SDK methods are generated
+	MethodArguments *string `yaml:"methodArguments,omitempty"`
+	// Specifies the module format to use when compiling the SDK.
+	ModuleFormat *string `yaml:"moduleFormat,omitempty"`
+	// The suffix to add to models with writeOnly fields that are created as input models
+	OutputModelSuffix *string `yaml:"outputModelSuffix,omitempty"`
+	// The npm package name. https://docs.npmjs.com/package-name-guidelines.
+	PackageName *string `yaml:"packageName,omitempty"`
+	// Determines the shape of the response envelope that is returned from SDK methods
+	ResponseFormat *string `yaml:"responseFormat,omitempty"`
+	// The template version to use
+	TemplateVersion *string `yaml:"templateVersion,omitempty"`
+	// Determine whether or not index modules (index.ts) are generated
+	UseIndexModules *bool `yaml:"useIndexModules,omitempty"`
+	// The current version of the SDK
+	Version string `yaml:"version"`
+	// Captures any keys that are not described by the schema
+	AdditionalProperties map[string]any `yaml:",inline"`
+}
+
+// UnityConfig is the typed configuration for unity SDKs, generated from schemas/languages/unity.schema.json.
+type UnityConfig struct {
+	// The name of the author of the published package. https://learn.microsoft.com/en-us/nuget/create-packages/package-authoring-best-practices#authors
+	Author *string `yaml:"author,omitempty"`
+	// Whether to treat 4xx and 5xx status codes as errors.
+	ClientServerStatusCodesAsErrors *bool `yaml:"clientServerStatusCodesAsErrors,omitempty"`
+	// The name of the default exception that is thrown when an API error occurs.
+	DefaultErrorName *string `yaml:"defaultErrorName,omitempty"`
+	// Whether to disable Pascal Casing sanitization on provided packageName when setting the root namespace and NuGet package ID.
+	DisableNamespacePascalCasingApr2024 *bool `yaml:"disableNamespacePascalCasingApr2024,omitempty"`
+	// Flatten the global security configuration if there is only a single option in the spec
+	FlattenGlobalSecurity *bool `yaml:"flattenGlobalSecurity,omitempty"`
+	// The suffix to add to models with writeOnly fields that are created as input models
+	InputModelSuffix *string `yaml:"inputModelSuffix,omitempty"`
+	// The maximum number of parameters a method can have before the resulting SDK endpoint is no longer 'flattened' and an input object is created instead. 0 will use input objects always. https://www.speakeasy.com/docs/customize-sdks/methods
+	MaxMethodParams *float64 `yaml:"maxMethodParams,omitempty"`
+	// The suffix to add to models with writeOnly fields that are created as input models
+	OutputModelSuffix *string `yaml:"outputModelSuffix,omitempty"`
+	// The NuGet package ID, also used as the root namespace. https://learn.microsoft.com/en-us/dotnet/standard/design-guidelines/names-of-namespaces.
+	PackageName *string `yaml:"packageName,omitempty"`
+	// The current version of the SDK
+	Version string `yaml:"version"`
+	// Captures any keys that are not described by the schema
+	AdditionalProperties map[string]any `yaml:",inline"`
+}
+
+// NewTypedLanguageConfig returns a pointer to a zero value of the typed config for the given language,
+// or nil if the language has no schema.
+func NewTypedLanguageConfig(lang string) any {
+	switch lang {
+	case "csharp":
+		return &CSharpConfig{}
+	case "go":
+		return &GoConfig{}
+	case "java":
+		return &JavaConfig{}
+	case "php":
+		return &PHPConfig{}
+	case "postman":
+		return &PostmanConfig{}
+	case "python":
+		return &PythonConfig{}
+	case "ruby":
+		return &RubyConfig{}
+	case "terraform":
+		return &TerraformConfig{}
+	case "typescript":
+		return &TypeScriptConfig{}
+	case "unity":
+		return &UnityConfig{}
+	}
+
+	return nil
+}
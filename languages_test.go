@@ -0,0 +1,98 @@
+package config_test
+
+//go:generate sh -c "cd tools/lang-gen && go run . -out ../../languages_gen.go"
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/speakeasy-api/openapi/pointer"
+	config "github.com/speakeasy-api/sdk-gen-config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsTyped_RoundTrip(t *testing.T) {
+	lc := config.LanguageConfig{
+		Version: "1.2.3",
+		Cfg: map[string]any{
+			"packageName":           "github.com/speakeasy-api/sdk",
+			"maxMethodParams":       4,
+			"envVarPrefix":          "SPEAKEASY",
+			"flattenGlobalSecurity": false,
+			"someFutureOption": map[string]any{
+				"nested": true,
+			},
+		},
+	}
+
+	goCfg, err := config.AsTyped[config.GoConfig](lc)
+	require.NoError(t, err)
+
+	assert.Equal(t, "1.2.3", goCfg.Version)
+	assert.Equal(t, pointer.From("github.com/speakeasy-api/sdk"), goCfg.PackageName)
+	assert.Equal(t, pointer.From(4.0), goCfg.MaxMethodParams)
+	assert.Equal(t, "SPEAKEASY", goCfg.EnvVarPrefix)
+	assert.Equal(t, pointer.From(false), goCfg.FlattenGlobalSecurity)
+	assert.Nil(t, goCfg.ResponseFormat)
+	assert.Equal(t, map[string]any{"someFutureOption": map[string]any{"nested": true}}, goCfg.AdditionalProperties)
+
+	goCfg.PackageName = pointer.From("github.com/speakeasy-api/sdk-v2")
+
+	roundTripped, err := config.FromTyped(goCfg)
+	require.NoError(t, err)
+
+	want := lc
+	want.Cfg = map[string]any{
+		"packageName":           "github.com/speakeasy-api/sdk-v2",
+		"maxMethodParams":       4,
+		"envVarPrefix":          "SPEAKEASY",
+		"flattenGlobalSecurity": false,
+		"someFutureOption": map[string]any{
+			"nested": true,
+		},
+	}
+	assert.Equal(t, want, roundTripped)
+}
+
+func TestAsTyped_InvalidType(t *testing.T) {
+	_, err := config.AsTyped[config.TypeScriptConfig](config.LanguageConfig{
+		Version: "1.0.0",
+		Cfg: map[string]any{
+			"enableReactQuery": "definitely",
+		},
+	})
+	assert.ErrorContains(t, err, "could not convert language config to config.TypeScriptConfig")
+}
+
+func TestNewTypedLanguageConfig(t *testing.T) {
+	assert.IsType(t, &config.GoConfig{}, config.NewTypedLanguageConfig("go"))
+	assert.IsType(t, &config.TypeScriptConfig{}, config.NewTypedLanguageConfig("typescript"))
+	assert.IsType(t, &config.CSharpConfig{}, config.NewTypedLanguageConfig("csharp"))
+	assert.Nil(t, config.NewTypedLanguageConfig("cobol"))
+}
+
+// TestLanguageConfigsInSync verifies that languages_gen.go is in sync with
+// what the generator produces from schemas/languages.
+func TestLanguageConfigsInSync(t *testing.T) {
+	cmd := exec.Command("go", "run", ".", "-out", "-")
+	cmd.Dir = filepath.Join("tools", "lang-gen")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	require.NoError(t, err, "language config generator failed: %s", stderr.String())
+
+	committedBytes, err := os.ReadFile("languages_gen.go")
+	require.NoError(t, err, "Failed to read committed languages_gen.go")
+
+	require.Equal(t, string(committedBytes), stdout.String(),
+		"Generated language configs do not match committed languages_gen.go.\n"+
+			"Run: cd tools/lang-gen && go run . -out ../../languages_gen.go\n"+
+			"Then commit the updated file.")
+}
@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
-	"os"
 	"regexp"
 	"slices"
 	"time"
@@ -111,17 +110,24 @@ type Lint struct {
 	CustomRules    *CustomRulesConfig `yaml:"customRules,omitempty"`
 }
 
-func Load(searchDirs []string) (*Lint, string, error) {
+// Load finds and loads the lint.yaml in searchDirs or their parents, falling back to the user's home directory.
+func Load(searchDirs []string, opts ...workspace.Option) (*Lint, string, error) {
+	o := workspace.ApplyOptions(opts)
+
 	var res *workspace.FindWorkspaceResult
 
 	dirsToSearch := map[string]bool{}
 
 	for _, dir := range searchDirs {
-		dirsToSearch[dir] = true
+		absDir, err := o.Abs(dir)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get absolute path: %w", err)
+		}
+		dirsToSearch[absDir] = true
 	}
 
 	// Allow searching in the user's home directory
-	homeDir, err := os.UserHomeDir()
+	homeDir, err := o.UserHomeDir()
 	if err == nil {
 		dirsToSearch[homeDir] = false
 	}
@@ -132,6 +138,7 @@ func Load(searchDirs []string) (*Lint, string, error) {
 		res, err = workspace.FindWorkspace(dir, workspace.FindWorkspaceOptions{
 			FindFile:  lintFile,
 			Recursive: allowRecursive,
+			FS:        o.FS,
 		})
 		if err != nil {
 			if !errors.Is(err, fs.ErrNotExist) {
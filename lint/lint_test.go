@@ -1,6 +1,7 @@
 package lint_test
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -8,6 +9,8 @@ import (
 	"time"
 
 	"github.com/speakeasy-api/sdk-gen-config/lint"
+	"github.com/speakeasy-api/sdk-gen-config/memfs"
+	"github.com/speakeasy-api/sdk-gen-config/workspace"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -245,6 +248,39 @@ rulesets:
 	}
 }
 
+func TestLint_Load_FileSystem(t *testing.T) {
+	lintContents := `lintVersion: 1.0.0
+defaultRuleset: %s
+rulesets:
+  %s:
+    rulesets:
+      - recommended
+`
+
+	fsys := memfs.New()
+	require.NoError(t, fsys.WriteFile("/home/user/.speakeasy/lint.yaml", []byte(fmt.Sprintf(lintContents, "home", "home")), 0o644))
+
+	env := map[string]string{"HOME": "/home/user", "USERPROFILE": "/home/user", "home": "/home/user"}
+	lookupEnv := func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+
+	lintFile, lintPath, err := lint.Load([]string{"repo"}, workspace.WithFileSystem(fsys), workspace.WithWorkingDir("/work"), workspace.WithLookupEnv(lookupEnv))
+	require.NoError(t, err)
+	assert.Equal(t, "home", lintFile.DefaultRuleset)
+	assert.Equal(t, filepath.Join("/home/user", ".speakeasy", "lint.yaml"), lintPath)
+
+	require.NoError(t, fsys.WriteFile("/work/.speakeasy/lint.yaml", []byte(fmt.Sprintf(lintContents, "work", "work")), 0o644))
+
+	lintFile, _, err = lint.Load([]string{"repo"}, workspace.WithFileSystem(fsys), workspace.WithWorkingDir("/work"), workspace.WithHomeDir("/elsewhere"))
+	require.NoError(t, err)
+	assert.Equal(t, "work", lintFile.DefaultRuleset)
+
+	_, _, err = lint.Load([]string{"/other"}, workspace.WithFileSystem(fsys), workspace.WithHomeDir("/elsewhere"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
 func createTempFile(dir string, fileName, contents string) error {
 	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
 		return err
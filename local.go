@@ -0,0 +1,146 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+// localConfigFile is an optional, git-ignored file next to gen.yaml whose values are deep-merged over gen.yaml
+// when loading. Its values are never written back into gen.yaml.
+const localConfigFile = "gen.local.yaml"
+
+// readLocalLayer returns the gen.local.yaml next to configPath, or nil if there isn't one.
+func readLocalLayer(configPath string, o *options) (*configLayer, error) {
+	localPath := filepath.Join(filepath.Dir(configPath), localConfigFile)
+
+	data, err := readFile(localPath, o)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("could not read %s: %w", localConfigFile, err)
+	}
+
+	return newConfigLayer(SourceLocal, localPath, data)
+}
+
+// mergeLayer returns a copy of base with the values in layer deep-merged over it.
+// Maps are merged key by key, any other value in layer replaces the value in base.
+func mergeLayer(base, layer map[string]any) map[string]any {
+	out := make(map[string]any, len(base)+len(layer))
+	for k, v := range base {
+		out[k] = v
+	}
+
+	for k, lv := range layer {
+		lm, lok := lv.(map[string]any)
+		bm, bok := out[k].(map[string]any)
+		if lok && bok {
+			out[k] = mergeLayer(bm, lm)
+			continue
+		}
+		out[k] = lv
+	}
+
+	return out
+}
+
+// unmergeLayer removes the values contributed by layer from out, restoring the value from committed where there was one.
+// Values that no longer match the layer were changed after loading and are kept.
+func unmergeLayer(out, layer, committed map[string]any) {
+	for k, lv := range layer {
+		cv, committedOK := committed[k]
+
+		ov, ok := out[k]
+		if !ok {
+			// Zero values from the layer are dropped by omitempty when marshaling, but the committed value still needs restoring
+			if committedOK && isZeroLayerValue(lv) {
+				out[k] = cv
+			}
+			continue
+		}
+
+		lm, lok := lv.(map[string]any)
+		om, ook := ov.(map[string]any)
+		if lok && ook {
+			cm, _ := cv.(map[string]any)
+			unmergeLayer(om, lm, cm)
+			if len(om) == 0 && !committedOK {
+				delete(out, k)
+			}
+			continue
+		}
+
+		if !reflect.DeepEqual(ov, lv) {
+			continue
+		}
+
+		if committedOK {
+			out[k] = cv
+		} else {
+			delete(out, k)
+		}
+	}
+}
+
+func isZeroLayerValue(v any) bool {
+	if m, ok := v.(map[string]any); ok {
+		for _, child := range m {
+			if !isZeroLayerValue(child) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return v == nil || reflect.ValueOf(v).IsZero()
+}
+
+// withoutLayers returns cfg as a map with the values contributed by gen.local.yaml and any extended base configs
+// stripped, ready to be written over original.
+func withoutLayers(cfg any, layers *configLayers, original []byte) (map[string]any, error) {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	out := map[string]any{}
+	if err := yaml.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+
+	committed := map[string]any{}
+	if len(original) > 0 {
+		if err := yaml.Unmarshal(original, &committed); err != nil {
+			return nil, err
+		}
+	}
+
+	if layers.local != nil {
+		unmergeLayer(out, layers.local.values, committed)
+	}
+	if len(layers.bases) > 0 {
+		unmergeLayer(out, layers.baseValues(), committed)
+	}
+
+	return out, nil
+}
+
+// writeConfig writes cfg to the gen.yaml at path, leaving out any values that came from other layers.
+func writeConfig(path string, cfg any, original []byte, layers *configLayers, o *options) ([]byte, error) {
+	if !layers.layered() {
+		return write(path, cfg, original, o)
+	}
+
+	stripped, err := withoutLayers(cfg, layers, original)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal %s: %w", path, err)
+	}
+
+	return write(path, stripped, original, o)
+}
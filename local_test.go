@@ -0,0 +1,103 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/speakeasy-api/sdk-gen-config/lockfile"
+	"github.com/speakeasy-api/sdk-gen-config/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_MergesLocalConfig(t *testing.T) {
+	getUUID = func() string {
+		return "123"
+	}
+	lockfile.GetUUID = getUUID
+
+	genYaml := `configVersion: 2.0.0
+generation:
+  sdkClassName: speakeasy
+  tests:
+    generateTests: true
+go:
+  version: 1.0.0
+  packageName: openapi
+`
+
+	dir := t.TempDir()
+	speakeasyDir := filepath.Join(dir, ".speakeasy")
+	testutils.CreateTempFile(t, speakeasyDir, "gen.yaml", genYaml)
+	testutils.CreateTempFile(t, speakeasyDir, "gen.lock", testutils.ReadTestFile(t, "v200-gen.lock"))
+	testutils.CreateTempFile(t, speakeasyDir, "gen.local.yaml", `generation:
+  tests:
+    generateTests: false
+  maintainOpenAPIOrder: false
+go:
+  version: 1.0.1-local
+`)
+
+	cfg, err := Load(dir, WithUpgradeFunc(testUpdateLang), WithLanguages("go"))
+	require.NoError(t, err)
+
+	assert.False(t, cfg.Config.Generation.Tests.GenerateTests)
+	assert.False(t, cfg.Config.Generation.MaintainOpenAPIOrder)
+	assert.Equal(t, "speakeasy", cfg.Config.Generation.SDKClassName)
+	assert.Equal(t, "1.0.1-local", cfg.Config.Languages["go"].Version)
+	assert.Equal(t, "openapi", cfg.Config.Languages["go"].Cfg["packageName"])
+
+	data, err := os.ReadFile(filepath.Join(speakeasyDir, "gen.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "  tests:\n    generateTests: true\n")
+	assert.Contains(t, string(data), "go:\n  version: 1.0.0\n")
+	assert.NotContains(t, string(data), "maintainOpenAPIOrder")
+
+	// Values changed after loading are saved, overridden ones keep their committed values
+	cfg.Config.Generation.SDKClassName = "MySDK"
+	require.NoError(t, SaveConfig(dir, cfg.Config))
+
+	data, err = os.ReadFile(filepath.Join(speakeasyDir, "gen.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "sdkClassName: MySDK\n")
+	assert.Contains(t, string(data), "  tests:\n    generateTests: true\n")
+	assert.Contains(t, string(data), "go:\n  version: 1.0.0\n")
+	assert.NotContains(t, string(data), "maintainOpenAPIOrder")
+}
+
+func TestUnmergeLayer(t *testing.T) {
+	layer := map[string]any{
+		"generation": map[string]any{
+			"tests": map[string]any{"generateTests": false},
+			"added": true,
+		},
+		"go": map[string]any{"version": "2.0.0"},
+	}
+	committed := map[string]any{
+		"generation": map[string]any{
+			"tests": map[string]any{"generateTests": true},
+		},
+		"go": map[string]any{"version": "1.0.0"},
+	}
+
+	out := mergeLayer(committed, layer)
+	assert.Equal(t, map[string]any{
+		"generation": map[string]any{
+			"tests": map[string]any{"generateTests": false},
+			"added": true,
+		},
+		"go": map[string]any{"version": "2.0.0"},
+	}, out)
+
+	// A value changed after merging is kept
+	out["go"] = map[string]any{"version": "3.0.0"}
+
+	unmergeLayer(out, layer, committed)
+	assert.Equal(t, map[string]any{
+		"generation": map[string]any{
+			"tests": map[string]any{"generateTests": true},
+		},
+		"go": map[string]any{"version": "3.0.0"},
+	}, out)
+}
@@ -0,0 +1,143 @@
+package lockfile
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// GeneratedIDMarker marks the ID of a generated file in a comment in its header, for example
+// "// @generated-id: 0f8fad5b" or "# @generated-id: 0f8fad5b".
+const GeneratedIDMarker = "@generated-id"
+
+// generatedIDRegex matches the marker in a line comment (//, #, --), a block comment (/* */, <!-- -->) or a
+// continuation line of a block comment starting with *.
+var generatedIDRegex = regexp.MustCompile(`^(?://+|#+|--|/\*+|\*|<!--)\s*` + GeneratedIDMarker + `:?\s*([A-Za-z0-9][A-Za-z0-9._-]*)\s*(?:\*/|-->)?$`)
+
+// headerWindow is the number of bytes at the start of a file read to find its header.
+const headerWindow = 4096
+
+// ParseGeneratedID returns the ID in the @generated-id comment within the header of a file, the first 4KB of data.
+func ParseGeneratedID(data []byte) (string, bool) {
+	if len(data) > headerWindow {
+		data = data[:headerWindow]
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.Contains(line, GeneratedIDMarker) {
+			continue
+		}
+		if m := generatedIDRegex.FindStringSubmatch(line); m != nil {
+			return m[1], true
+		}
+	}
+
+	return "", false
+}
+
+// ReadGeneratedID returns the ID in the @generated-id header of the file at path, or an empty string if it has none.
+func ReadGeneratedID(fileSystem fs.FS, path string) (string, error) {
+	head, err := readHeader(fileSystem, path)
+	if err != nil {
+		return "", err
+	}
+	id, _ := ParseGeneratedID(head)
+	return id, nil
+}
+
+// GeneratedIDIndex maps the IDs in @generated-id headers to the paths of the files containing them. An ID maps to
+// more than one path if a generated file was copied.
+type GeneratedIDIndex map[string][]string
+
+// Path returns the path of the file with id, if exactly one file has it.
+func (idx GeneratedIDIndex) Path(id string) (string, bool) {
+	paths := idx[id]
+	if len(paths) != 1 {
+		return "", false
+	}
+	return paths[0], true
+}
+
+// IndexGeneratedIDs reads the @generated-id header of every file in fileSystem. Hidden directories, node_modules and
+// vendor are skipped.
+func IndexGeneratedIDs(fileSystem fs.FS) (GeneratedIDIndex, error) {
+	idx := GeneratedIDIndex{}
+
+	err := walkFiles(fileSystem, func(path string) error {
+		id, err := ReadGeneratedID(fileSystem, path)
+		if err != nil {
+			return err
+		}
+		if id != "" {
+			idx[id] = append(idx[id], path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to index generated files: %w", err)
+	}
+
+	for _, paths := range idx {
+		slices.Sort(paths)
+	}
+
+	return idx, nil
+}
+
+// UpdateMovedAndDeleted scans fileSystem for the tracked files of lf and sets MovedTo and Deleted on them, using
+// @generated-id headers and checksums to find moved files. The scan is returned for reporting.
+func UpdateMovedAndDeleted(fileSystem fs.FS, lf *LockFile) (*ScanReport, error) {
+	report, err := Scan(fileSystem, lf)
+	if err != nil {
+		return nil, err
+	}
+	report.Apply(lf)
+	return report, nil
+}
+
+// walkFiles calls fn with the path of every regular file in fileSystem, skipping hidden directories and those in
+// skippedScanDirs.
+func walkFiles(fileSystem fs.FS, fn func(path string) error) error {
+	return fs.WalkDir(fileSystem, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if p != "." && (strings.HasPrefix(d.Name(), ".") || skippedScanDirs[d.Name()]) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		return fn(p)
+	})
+}
+
+// readHeader returns the first headerWindow bytes of the file at path.
+func readHeader(fileSystem fs.FS, path string) ([]byte, error) {
+	f, err := fileSystem.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	head := make([]byte, headerWindow)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return head[:n], nil
+}
@@ -0,0 +1,99 @@
+package lockfile_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/speakeasy-api/sdk-gen-config/lockfile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGeneratedID(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		wantID string
+	}{
+		{name: "go", data: "// Code generated by Speakeasy (https://speakeasy.com). DO NOT EDIT.\n// @generated-id: 0f8fad5b-d9cb\n\npackage sdk\n", wantID: "0f8fad5b-d9cb"},
+		{name: "typescript block comment", data: "/*\n * Code generated by Speakeasy (https://speakeasy.com). DO NOT EDIT.\n * @generated-id: a1b2c3\n */\n", wantID: "a1b2c3"},
+		{name: "single line block comment", data: "/* @generated-id: a1b2c3 */\nclass Foo {}\n", wantID: "a1b2c3"},
+		{name: "python", data: "\"\"\"Code generated by Speakeasy (https://speakeasy.com). DO NOT EDIT.\"\"\"\n# @generated-id: py-123\n", wantID: "py-123"},
+		{name: "ruby without colon", data: "# typed: true\n# @generated-id py_456\n", wantID: "py_456"},
+		{name: "sql and lua", data: "-- @generated-id: lua.789\n", wantID: "lua.789"},
+		{name: "markdown", data: "<!-- @generated-id: docs-1 -->\n# Models\n", wantID: "docs-1"},
+		{name: "indented", data: "    // @generated-id: indented\n", wantID: "indented"},
+		{name: "not a comment", data: "const marker = \"@generated-id: fake\";\n"},
+		{name: "no header", data: "package sdk\n"},
+		{name: "beyond the header", data: string(make([]byte, 5000)) + "\n// @generated-id: late\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, ok := lockfile.ParseGeneratedID([]byte(tt.data))
+			assert.Equal(t, tt.wantID, id)
+			assert.Equal(t, tt.wantID != "", ok)
+		})
+	}
+}
+
+func TestIndexGeneratedIDs(t *testing.T) {
+	fsys := fstest.MapFS{
+		"src/a.ts":              {Data: []byte("// @generated-id: a\n")},
+		"src/copy/a.ts":         {Data: []byte("// @generated-id: a\n")},
+		"src/b.py":              {Data: []byte("# @generated-id: b\n")},
+		"src/c.ts":              {Data: []byte("export {};\n")},
+		".git/objects/x":        {Data: []byte("// @generated-id: hidden\n")},
+		"node_modules/dep/a.js": {Data: []byte("// @generated-id: dep\n")},
+	}
+
+	idx, err := lockfile.IndexGeneratedIDs(fsys)
+	require.NoError(t, err)
+	assert.Equal(t, lockfile.GeneratedIDIndex{
+		"a": {"src/a.ts", "src/copy/a.ts"},
+		"b": {"src/b.py"},
+	}, idx)
+
+	p, ok := idx.Path("b")
+	assert.True(t, ok)
+	assert.Equal(t, "src/b.py", p)
+	_, ok = idx.Path("a")
+	assert.False(t, ok, "copies are ambiguous")
+
+	id, err := lockfile.ReadGeneratedID(fsys, "src/b.py")
+	require.NoError(t, err)
+	assert.Equal(t, "b", id)
+}
+
+func TestUpdateMovedAndDeleted(t *testing.T) {
+	fsys := fstest.MapFS{
+		// Moved and then edited, so only the ID matches
+		"src/renamed/user.ts": {Data: []byte("// @generated-id: user-id\nexport type User = { name: string; edited: true };\n")},
+		"src/pet.ts":          {Data: []byte("// @generated-id: pet-id\nexport type Pet = {};\n")},
+		// Copied twice, so the move is ambiguous
+		"src/a/order.ts": {Data: []byte("// @generated-id: order-id\n")},
+		"src/b/order.ts": {Data: []byte("// @generated-id: order-id\n")},
+	}
+
+	lf := newTrackedLockFile()
+	lf.TrackedFiles.Set("src/user.ts", lockfile.TrackedFile{ID: "user-id", LastWriteChecksum: "sha1:original"})
+	lf.TrackedFiles.Set("src/pet.ts", lockfile.TrackedFile{ID: "pet-id", MovedTo: "src/gone.ts"})
+	lf.TrackedFiles.Set("src/order.ts", lockfile.TrackedFile{ID: "order-id"})
+	lf.TrackedFiles.Set("src/store.ts", lockfile.TrackedFile{ID: "store-id"})
+
+	report, err := lockfile.UpdateMovedAndDeleted(fsys, lf)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"src/user.ts"}, report.Status(lockfile.FileMoved))
+	assert.Equal(t, []string{"src/order.ts", "src/store.ts"}, report.Status(lockfile.FileDeleted))
+	assert.Equal(t, []string{"src/a/order.ts", "src/b/order.ts"}, report.Untracked)
+
+	user, _ := lf.TrackedFiles.Get("src/user.ts")
+	assert.Equal(t, "src/renamed/user.ts", user.MovedTo)
+	assert.False(t, user.Deleted)
+
+	pet, _ := lf.TrackedFiles.Get("src/pet.ts")
+	assert.Empty(t, pet.MovedTo)
+	assert.False(t, pet.Deleted)
+
+	store, _ := lf.TrackedFiles.Get("src/store.ts")
+	assert.True(t, store.Deleted)
+}
@@ -0,0 +1,319 @@
+package lockfile
+
+import (
+	"bytes"
+	"crypto/sha1" // nolint:gosec // sha1 is intentional as we're matching git
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"slices"
+	"strings"
+)
+
+// ObjectFormat is the hash algorithm of a git repository, as set by git init --object-format.
+type ObjectFormat string
+
+const (
+	ObjectFormatSHA1   ObjectFormat = "sha1"
+	ObjectFormatSHA256 ObjectFormat = "sha256"
+)
+
+var ErrInvalidObjectFormat = errors.New("invalid git object format")
+
+// Git file modes of tree entries
+const (
+	ModeFile       = "100644"
+	ModeExecutable = "100755"
+	ModeSymlink    = "120000"
+	modeTree       = "40000"
+)
+
+func (f ObjectFormat) newHash() (hash.Hash, error) {
+	switch f {
+	case ObjectFormatSHA1:
+		return sha1.New(), nil // nolint:gosec
+	case ObjectFormatSHA256:
+		return sha256.New(), nil
+	default:
+		return nil, fmt.Errorf("%w: %q must be sha1 or sha256", ErrInvalidObjectFormat, f)
+	}
+}
+
+// hashObject returns the hash of a git object of kind with the given contents.
+func hashObject(format ObjectFormat, kind string, size int64, r io.Reader) ([]byte, error) {
+	h, err := format.newHash()
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(h, "%s %d\x00", kind, size)
+	n, err := io.Copy(h, r)
+	if err != nil {
+		return nil, err
+	}
+	if n != size {
+		return nil, fmt.Errorf("expected %d bytes, read %d", size, n)
+	}
+
+	return h.Sum(nil), nil
+}
+
+// HashBlob returns the hex hash of data as a git blob, matching git hash-object. Unlike the checksums of tracked files
+// the data isn't normalized, so line endings must already be as git would store them.
+func HashBlob(format ObjectFormat, data []byte) (string, error) {
+	sum, err := hashObject(format, "blob", int64(len(data)), bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(sum), nil
+}
+
+// HashBlobFile returns the hex hash of the file at path as a git blob, matching git hash-object.
+func HashBlobFile(format ObjectFormat, fileSystem fs.FS, path string) (string, error) {
+	f, err := fileSystem.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", fmt.Errorf("stat %s: %w", path, err)
+	}
+
+	sum, err := hashObject(format, "blob", info.Size(), f)
+	if err != nil {
+		return "", fmt.Errorf("hash %s: %w", path, err)
+	}
+	return hex.EncodeToString(sum), nil
+}
+
+// TreeEntry is a file in a git tree.
+type TreeEntry struct {
+	Path string // The path of the file using forward slashes
+	Mode string // One of ModeFile, ModeExecutable or ModeSymlink, defaults to ModeFile
+	Hash string // The hex hash of the blob
+}
+
+type treeNode struct {
+	mode     string
+	sum      []byte // The hash of a file, trees are hashed from their children
+	children map[string]*treeNode
+}
+
+// HashTree returns the hex hash of the git tree containing entries, matching git write-tree. Directories are created
+// for the paths of the entries.
+func HashTree(format ObjectFormat, entries []TreeEntry) (string, error) {
+	h, err := format.newHash()
+	if err != nil {
+		return "", err
+	}
+
+	root := &treeNode{children: map[string]*treeNode{}}
+
+	for _, e := range entries {
+		hash, err := hex.DecodeString(e.Hash)
+		if err != nil || len(hash) != h.Size() {
+			return "", fmt.Errorf("invalid %s hash %q for %s", format, e.Hash, e.Path)
+		}
+		mode := e.Mode
+		if mode == "" {
+			mode = ModeFile
+		}
+
+		parts := strings.Split(e.Path, "/")
+		node := root
+		for i, part := range parts {
+			if part == "" || part == "." || part == ".." {
+				return "", fmt.Errorf("invalid path %q", e.Path)
+			}
+
+			child, ok := node.children[part]
+			if i == len(parts)-1 {
+				if ok {
+					return "", fmt.Errorf("duplicate path %q", e.Path)
+				}
+				node.children[part] = &treeNode{mode: mode, sum: hash}
+				break
+			}
+
+			if !ok {
+				child = &treeNode{mode: modeTree, children: map[string]*treeNode{}}
+				node.children[part] = child
+			} else if child.children == nil {
+				return "", fmt.Errorf("path %q is both a file and a directory", e.Path)
+			}
+			node = child
+		}
+	}
+
+	sum, err := root.hash(format)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(sum), nil
+}
+
+func (n *treeNode) hash(format ObjectFormat) ([]byte, error) {
+	if n.children == nil {
+		return n.sum, nil
+	}
+
+	// Git sorts entries by name, comparing directories as if their names end with a slash
+	sortName := func(name string) string {
+		if n.children[name].children != nil {
+			return name + "/"
+		}
+		return name
+	}
+	names := make([]string, 0, len(n.children))
+	for name := range n.children {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		return strings.Compare(sortName(a), sortName(b))
+	})
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		child := n.children[name]
+		sum, err := child.hash(format)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&buf, "%s %s\x00", child.mode, name)
+		buf.Write(sum)
+	}
+
+	return hashObject(format, "tree", int64(buf.Len()), &buf)
+}
+
+// readLinkFS is implemented by file systems that can report symlinks rather than following them, matching
+// fs.ReadLinkFS, which os.DirFS implements from Go 1.25.
+type readLinkFS interface {
+	fs.FS
+	ReadLink(name string) (string, error)
+	Lstat(name string) (fs.FileInfo, error)
+}
+
+// HashTrackedFilesTree returns the hex hash of the git tree of the tracked files of lf as they are in fileSystem,
+// which should be rooted at the directory containing the generated files (parent of .speakeasy/). Moved files are
+// hashed at the path they were moved to, deleted files are left out.
+//
+// If fileSystem can read links like fs.ReadLinkFS, symlinks are hashed as git stores them, with ModeSymlink and the
+// link target as their contents, otherwise the file they point to is hashed. The executable bit isn't available on
+// Windows, so every file is hashed with ModeFile there and trees containing executable files won't match git's.
+func HashTrackedFilesTree(format ObjectFormat, fileSystem fs.FS, lf *LockFile) (string, error) {
+	_, entries, err := trackedTreeEntries(format, fileSystem, lf)
+	if err != nil {
+		return "", err
+	}
+	return HashTree(format, entries)
+}
+
+// trackedTreeEntries returns the tree entries of the tracked files of lf that weren't deleted, along with the paths
+// they are tracked at in gen.lock.
+func trackedTreeEntries(format ObjectFormat, fileSystem fs.FS, lf *LockFile) ([]string, []TreeEntry, error) {
+	var paths []string
+	var entries []TreeEntry
+
+	if lf.TrackedFiles != nil {
+		for path, tf := range lf.TrackedFiles.All() {
+			if tf.Deleted {
+				continue
+			}
+			filePath := path
+			if tf.MovedTo != "" {
+				filePath = tf.MovedTo
+			}
+
+			entry, err := hashTreeEntry(format, fileSystem, filePath)
+			if err != nil {
+				return nil, nil, err
+			}
+			paths = append(paths, path)
+			entries = append(entries, entry)
+		}
+	}
+
+	return paths, entries, nil
+}
+
+// hashTreeEntry returns the git tree entry of the file at path.
+func hashTreeEntry(format ObjectFormat, fileSystem fs.FS, path string) (TreeEntry, error) {
+	var info fs.FileInfo
+	var err error
+	if rl, ok := fileSystem.(readLinkFS); ok {
+		info, err = rl.Lstat(path)
+	} else {
+		info, err = fs.Stat(fileSystem, path)
+	}
+	if err != nil {
+		return TreeEntry{}, fmt.Errorf("stat %s: %w", path, err)
+	}
+
+	if info.Mode()&fs.ModeSymlink != 0 {
+		target, err := fileSystem.(readLinkFS).ReadLink(path)
+		if err != nil {
+			return TreeEntry{}, fmt.Errorf("readlink %s: %w", path, err)
+		}
+		hash, err := HashBlob(format, []byte(target))
+		if err != nil {
+			return TreeEntry{}, err
+		}
+		return TreeEntry{Path: path, Mode: ModeSymlink, Hash: hash}, nil
+	}
+
+	hash, err := HashBlobFile(format, fileSystem, path)
+	if err != nil {
+		return TreeEntry{}, err
+	}
+
+	mode := ModeFile
+	if info.Mode().Perm()&0o111 != 0 {
+		mode = ModeExecutable
+	}
+	return TreeEntry{Path: path, Mode: mode, Hash: hash}, nil
+}
+
+// PristineDiff compares the tracked files on disk with the pristine generation recorded in gen.lock.
+type PristineDiff struct {
+	TreeHash string // The hash of the tree of the tracked files on disk
+	// TreeUnchanged is true if TreeHash equals PersistentEdits.PristineTreeHash, meaning no tracked file was edited
+	TreeUnchanged bool
+	// Changed lists the tracked files whose blob hash differs from their pristine_git_object, in gen.lock order.
+	// Files without a pristine_git_object are left out.
+	Changed []string
+}
+
+// DiffPristine hashes the tracked files of lf as they are in fileSystem, like HashTrackedFilesTree, and compares them
+// with the pristine_git_object of each file and the pristine_tree_hash of persistentEdits. format must be the object
+// format the pristine hashes were written with.
+func DiffPristine(format ObjectFormat, fileSystem fs.FS, lf *LockFile) (*PristineDiff, error) {
+	paths, entries, err := trackedTreeEntries(format, fileSystem, lf)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := HashTree(format, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &PristineDiff{
+		TreeHash:      tree,
+		TreeUnchanged: lf.PersistentEdits != nil && lf.PersistentEdits.PristineTreeHash == tree,
+	}
+	for i, path := range paths {
+		tf, _ := lf.TrackedFiles.Get(path)
+		if tf.PristineGitObject != "" && tf.PristineGitObject != entries[i].Hash {
+			diff.Changed = append(diff.Changed, path)
+		}
+	}
+
+	return diff, nil
+}
@@ -0,0 +1,254 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"os"
+	"runtime"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/speakeasy-api/sdk-gen-config/lockfile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gitTreeFixture holds the hashes git gives the files in testdata/gittree, from git ls-tree -r HEAD and
+// git rev-parse HEAD^{tree} after committing them to repositories created with git init --object-format=sha1 and
+// --object-format=sha256.
+var gitTreeFixture = map[lockfile.ObjectFormat]struct {
+	tree  string
+	blobs []lockfile.TreeEntry
+}{
+	lockfile.ObjectFormatSHA1: {
+		tree: "29dcc36620e31c80ad56e8c8aef57f0d232343aa",
+		blobs: []lockfile.TreeEntry{
+			{Path: "README.md", Mode: lockfile.ModeFile, Hash: "1d82aa46dabbb44fbcc02220145741041e97120a"},
+			{Path: "crlf.txt", Mode: lockfile.ModeFile, Hash: "cf9b2a85b62bc2fd67c5ed43a1d0009df848ac8a"},
+			{Path: "empty.txt", Mode: lockfile.ModeFile, Hash: "e69de29bb2d1d6434b8b29ae775ad8c2e48c5391"},
+			{Path: "scripts/build.sh", Mode: lockfile.ModeExecutable, Hash: "046a82c4cca993f16f1503fd5b6d1ca5dd130274"},
+			{Path: "src/index.ts", Mode: lockfile.ModeFile, Hash: "609c2bb2c6cc49e77a9de0b8829563ae19a79ef7"},
+			{Path: "src/models-old.ts", Mode: lockfile.ModeFile, Hash: "b9d54b95c955aedf9ed6835fff6f9359f2889b3d"},
+			{Path: "src/models.ts", Mode: lockfile.ModeFile, Hash: "70e6d97ae2fb6bdaf1a4652677e984528663313b"},
+			{Path: "src/models/user.ts", Mode: lockfile.ModeFile, Hash: "b4170ec1c36f08852173c5687cc68f06ebda46a9"},
+		},
+	},
+	lockfile.ObjectFormatSHA256: {
+		tree: "c90ba66ee3cac1263cd61db03da9968228177c9c9bcc8fcbaa82c405c61a90ca",
+		blobs: []lockfile.TreeEntry{
+			{Path: "README.md", Mode: lockfile.ModeFile, Hash: "e274fe3ce15df41a305f73664b018d23659800e9351262c8cd7903da0a1b0393"},
+			{Path: "crlf.txt", Mode: lockfile.ModeFile, Hash: "ae945049b0a090edb61deba9641a6725ac966cba992e27cfdc96de38f40717b8"},
+			{Path: "empty.txt", Mode: lockfile.ModeFile, Hash: "473a0f4c3be8a93681a267e3b1e9a7dcda1185436fe141f7749120a303721813"},
+			{Path: "scripts/build.sh", Mode: lockfile.ModeExecutable, Hash: "ca2893c72a75770982e43a1beaa7f7f6bd5da033716b1d8c55c6e3d87f11d4b7"},
+			{Path: "src/index.ts", Mode: lockfile.ModeFile, Hash: "d428e6e6313684cea7534cfa46b2ccd4b39afc02d6601545bacfb81ed7fdcece"},
+			{Path: "src/models-old.ts", Mode: lockfile.ModeFile, Hash: "62ae1a2be2d5e5365ab13d0dc56fbf36fdaf3a4a3e5ea842865f4fe843fcfb3e"},
+			{Path: "src/models.ts", Mode: lockfile.ModeFile, Hash: "154705bcf53fe701794a04dd6468d32295245157054891f1f96bff3c3cab75fa"},
+			{Path: "src/models/user.ts", Mode: lockfile.ModeFile, Hash: "bbfc87874df892711b2216fbcbb9dd1a9099d8ec1da798b5f5e02ae34406a4fb"},
+		},
+	},
+}
+
+func TestHashBlob(t *testing.T) {
+	fsys := os.DirFS("testdata/gittree")
+
+	for format, fixture := range gitTreeFixture {
+		t.Run(string(format), func(t *testing.T) {
+			for _, blob := range fixture.blobs {
+				hash, err := lockfile.HashBlobFile(format, fsys, blob.Path)
+				require.NoError(t, err)
+				assert.Equal(t, blob.Hash, hash, blob.Path)
+
+				data, err := os.ReadFile("testdata/gittree/" + blob.Path)
+				require.NoError(t, err)
+				hash, err = lockfile.HashBlob(format, data)
+				require.NoError(t, err)
+				assert.Equal(t, blob.Hash, hash, blob.Path)
+			}
+		})
+	}
+
+	_, err := lockfile.HashBlob("md5", nil)
+	assert.ErrorIs(t, err, lockfile.ErrInvalidObjectFormat)
+}
+
+func TestHashTree(t *testing.T) {
+	for format, fixture := range gitTreeFixture {
+		t.Run(string(format), func(t *testing.T) {
+			// Order doesn't matter, git's is used
+			entries := append([]lockfile.TreeEntry{}, fixture.blobs...)
+			entries[0], entries[len(entries)-1] = entries[len(entries)-1], entries[0]
+
+			tree, err := lockfile.HashTree(format, entries)
+			require.NoError(t, err)
+			assert.Equal(t, fixture.tree, tree)
+
+			_, err = lockfile.HashTree(format, append(entries, entries[0]))
+			assert.ErrorContains(t, err, "duplicate path")
+		})
+	}
+
+	// The empty tree
+	tree, err := lockfile.HashTree(lockfile.ObjectFormatSHA1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "4b825dc642cb6eb9a060e54bf8d69288fbee4904", tree)
+
+	_, err = lockfile.HashTree(lockfile.ObjectFormatSHA256, gitTreeFixture[lockfile.ObjectFormatSHA1].blobs)
+	assert.ErrorContains(t, err, "invalid sha256 hash")
+}
+
+func TestHashTrackedFilesTree(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the executable bit isn't available on Windows")
+	}
+
+	lf := newTrackedLockFile()
+	for _, blob := range gitTreeFixture[lockfile.ObjectFormatSHA1].blobs {
+		lf.TrackedFiles.Set(blob.Path, lockfile.TrackedFile{})
+	}
+	lf.TrackedFiles.Set("src/removed.ts", lockfile.TrackedFile{Deleted: true})
+
+	for format, fixture := range gitTreeFixture {
+		tree, err := lockfile.HashTrackedFilesTree(format, os.DirFS("testdata/gittree"), lf)
+		require.NoError(t, err)
+		assert.Equal(t, fixture.tree, tree, format)
+	}
+}
+
+// fixtureFS copies testdata/gittree into a MapFS, keeping file modes.
+func fixtureFS(t *testing.T) fstest.MapFS {
+	t.Helper()
+
+	fsys := fstest.MapFS{}
+	root := os.DirFS("testdata/gittree")
+	err := fs.WalkDir(root, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		data, err := fs.ReadFile(root, p)
+		if err != nil {
+			return err
+		}
+		fsys[p] = &fstest.MapFile{Data: data, Mode: info.Mode()}
+		return nil
+	})
+	require.NoError(t, err)
+	return fsys
+}
+
+func TestHashTrackedFilesTree_NoExecutableBit(t *testing.T) {
+	// File systems without the executable bit, as on Windows, hash every file with ModeFile
+	fsys := fixtureFS(t)
+	for _, f := range fsys {
+		f.Mode = 0o644
+	}
+
+	fixture := gitTreeFixture[lockfile.ObjectFormatSHA1]
+	lf := newTrackedLockFile()
+	var entries []lockfile.TreeEntry
+	for _, blob := range fixture.blobs {
+		lf.TrackedFiles.Set(blob.Path, lockfile.TrackedFile{})
+		entries = append(entries, lockfile.TreeEntry{Path: blob.Path, Mode: lockfile.ModeFile, Hash: blob.Hash})
+	}
+
+	want, err := lockfile.HashTree(lockfile.ObjectFormatSHA1, entries)
+	require.NoError(t, err)
+
+	tree, err := lockfile.HashTrackedFilesTree(lockfile.ObjectFormatSHA1, fsys, lf)
+	require.NoError(t, err)
+	assert.Equal(t, want, tree)
+	assert.NotEqual(t, fixture.tree, tree)
+}
+
+// symlinkFS adds symlinks to a file system, reporting them from Lstat and ReadLink like os.DirFS.
+type symlinkFS struct {
+	fs.FS
+	links map[string]string
+}
+
+func (s symlinkFS) Open(name string) (fs.File, error) {
+	if target, ok := s.links[name]; ok {
+		return s.FS.Open(target)
+	}
+	return s.FS.Open(name)
+}
+
+func (s symlinkFS) ReadLink(name string) (string, error) {
+	target, ok := s.links[name]
+	if !ok {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+	return target, nil
+}
+
+func (s symlinkFS) Lstat(name string) (fs.FileInfo, error) {
+	if _, ok := s.links[name]; ok {
+		return linkInfo(name), nil
+	}
+	return fs.Stat(s.FS, name)
+}
+
+type linkInfo string
+
+func (l linkInfo) Name() string       { return string(l) }
+func (l linkInfo) Size() int64        { return 0 }
+func (l linkInfo) Mode() fs.FileMode  { return fs.ModeSymlink | 0o777 }
+func (l linkInfo) ModTime() time.Time { return time.Time{} }
+func (l linkInfo) IsDir() bool        { return false }
+func (l linkInfo) Sys() any           { return nil }
+
+func TestHashTrackedFilesTree_Symlink(t *testing.T) {
+	lf := newTrackedLockFile("a.txt", "link")
+
+	// From git ls-tree -r HEAD and git rev-parse HEAD^{tree} after committing a.txt and a symlink to it
+	fsys := symlinkFS{
+		FS:    fstest.MapFS{"a.txt": &fstest.MapFile{Data: []byte("x")}},
+		links: map[string]string{"link": "a.txt"},
+	}
+	tree, err := lockfile.HashTrackedFilesTree(lockfile.ObjectFormatSHA1, fsys, lf)
+	require.NoError(t, err)
+	assert.Equal(t, "389f5357b96994410039ceef0c037d740a13db30", tree)
+
+	want, err := lockfile.HashTree(lockfile.ObjectFormatSHA1, []lockfile.TreeEntry{
+		{Path: "a.txt", Hash: "c1b0730e0133447badcfd47fd144e254807b06e1"},
+		{Path: "link", Mode: lockfile.ModeSymlink, Hash: "8d14cbf983b3fad683171c9418998d9f68340823"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, want, tree)
+}
+
+func TestDiffPristine(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the executable bit isn't available on Windows")
+	}
+
+	fsys := fixtureFS(t)
+	fixture := gitTreeFixture[lockfile.ObjectFormatSHA1]
+
+	lf := newTrackedLockFile()
+	lf.PersistentEdits = &lockfile.PersistentEdits{PristineTreeHash: fixture.tree}
+	for _, blob := range fixture.blobs {
+		lf.TrackedFiles.Set(blob.Path, lockfile.TrackedFile{PristineGitObject: blob.Hash})
+	}
+
+	diff, err := lockfile.DiffPristine(lockfile.ObjectFormatSHA1, fsys, lf)
+	require.NoError(t, err)
+	assert.Equal(t, fixture.tree, diff.TreeHash)
+	assert.True(t, diff.TreeUnchanged)
+	assert.Empty(t, diff.Changed)
+
+	// Edited files are reported by the path they are tracked at, files without a pristine hash are left out
+	fsys["src/index.ts"] = &fstest.MapFile{Data: []byte("edited\n")}
+	fsys["src/moved.ts"] = &fstest.MapFile{Data: []byte("edited\n")}
+	lf.TrackedFiles.Set("src/models.ts", lockfile.TrackedFile{PristineGitObject: fixture.blobs[6].Hash, MovedTo: "src/moved.ts"})
+	lf.TrackedFiles.Set("README.md", lockfile.TrackedFile{})
+	fsys["README.md"] = &fstest.MapFile{Data: []byte("edited\n")}
+
+	diff, err = lockfile.DiffPristine(lockfile.ObjectFormatSHA1, fsys, lf)
+	require.NoError(t, err)
+	assert.False(t, diff.TreeUnchanged)
+	assert.Equal(t, []string{"src/index.ts", "src/models.ts"}, diff.Changed)
+}
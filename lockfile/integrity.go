@@ -3,21 +3,35 @@ package lockfile
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"io/fs"
+	"runtime"
+	"strings"
+	"sync"
 )
 
 // ComputeFileChecksum returns a checksum string like "sha1:<hex>"
 // by hashing the normalized contents of root/relPath using the provided filesystem.
 func ComputeFileChecksum(fileSystem fs.FS, relPath string) (string, error) {
+	return computeFileChecksum(fileSystem, relPath, nil)
+}
+
+// computeFileChecksum is ComputeFileChecksum using hasher, if not nil, to reuse buffers.
+func computeFileChecksum(fileSystem fs.FS, relPath string, hasher *NormalizedSHA1Hasher) (string, error) {
 	f, err := fileSystem.Open(relPath)
 	if err != nil {
 		return "", fmt.Errorf("open %s: %w", relPath, err)
 	}
 	defer f.Close()
 
-	sumHex, err := HashNormalizedSHA1(f)
+	hash := HashNormalizedSHA1
+	if hasher != nil {
+		hash = hasher.HashNormalizedSHA1
+	}
+
+	sumHex, err := hash(f)
 	if err != nil {
 		return "", fmt.Errorf("hash %s: %w", relPath, err)
 	}
@@ -46,26 +60,206 @@ func HashNormalizedSHA1(r io.Reader) (string, error) {
 
 // PopulateMissingChecksums computes last_write_checksum for any TrackedFiles entries
 // where LastWriteChecksum is empty. The fileSystem should be rooted at the directory containing
-// the generated files (parent of .speakeasy/).
+// the generated files (parent of .speakeasy/). Files are hashed one at a time, as fileSystem may not be safe for
+// concurrent use. Files that can't be read are skipped, use PopulateMissingChecksumsContext to find out which or to
+// hash files concurrently.
 func PopulateMissingChecksums(lf *LockFile, fileSystem fs.FS) error {
+	_ = PopulateMissingChecksumsContext(context.Background(), lf, fileSystem, WithConcurrency(1))
+	return nil
+}
+
+// ChecksumOption configures PopulateMissingChecksumsContext.
+type ChecksumOption func(*checksumOptions)
+
+type checksumOptions struct {
+	concurrency int
+	hasher      *NormalizedSHA1Hasher
+	progress    func(ChecksumProgress)
+}
+
+// WithConcurrency sets the number of files hashed at once, defaults to GOMAXPROCS.
+func WithConcurrency(n int) ChecksumOption {
+	return func(o *checksumOptions) {
+		o.concurrency = n
+	}
+}
+
+// WithHasher shares hasher, and its buffers, with other callers.
+func WithHasher(hasher *NormalizedSHA1Hasher) ChecksumOption {
+	return func(o *checksumOptions) {
+		o.hasher = hasher
+	}
+}
+
+// WithProgress calls fn after each file is hashed. Calls are made one at a time from the calling goroutine.
+func WithProgress(fn func(ChecksumProgress)) ChecksumOption {
+	return func(o *checksumOptions) {
+		o.progress = fn
+	}
+}
+
+// ChecksumProgress reports a file hashed by PopulateMissingChecksumsContext.
+type ChecksumProgress struct {
+	Path  string
+	Err   error // Set if the checksum couldn't be computed
+	Done  int   // The number of files hashed so far, including this one
+	Total int   // The number of files missing a checksum
+}
+
+// ChecksumError is a tracked file whose checksum couldn't be computed.
+type ChecksumError struct {
+	Path string
+	Err  error
+}
+
+// Error returns the message of Err, which names the file.
+func (e ChecksumError) Error() string {
+	return e.Err.Error()
+}
+
+func (e ChecksumError) Unwrap() error {
+	return e.Err
+}
+
+// ChecksumErrors summarizes the tracked files whose checksums couldn't be computed, ordered as in the lock file.
+type ChecksumErrors struct {
+	Errors []ChecksumError
+	Total  int // The number of files missing a checksum
+}
+
+// maxChecksumErrors is the number of files listed by ChecksumErrors.Error
+const maxChecksumErrors = 10
+
+func (e *ChecksumErrors) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "failed to compute checksums for %d of %d tracked files", len(e.Errors), e.Total)
+	for i, fe := range e.Errors {
+		if i == maxChecksumErrors {
+			fmt.Fprintf(&sb, "\n  ... and %d more", len(e.Errors)-maxChecksumErrors)
+			break
+		}
+		fmt.Fprintf(&sb, "\n  %s", fe.Error())
+	}
+	return sb.String()
+}
+
+func (e *ChecksumErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		errs = append(errs, fe)
+	}
+	return errs
+}
+
+// PopulateMissingChecksumsContext computes last_write_checksum for any TrackedFiles entries where LastWriteChecksum
+// is empty, hashing files concurrently. The fileSystem should be rooted at the directory containing the generated
+// files (parent of .speakeasy/) and must be safe for concurrent use, unless WithConcurrency(1) is set.
+//
+// Checksums that can be computed are always set. If any file can't be hashed a *ChecksumErrors listing them is
+// returned. If ctx is cancelled the files hashed so far are kept and the context's error is returned.
+func PopulateMissingChecksumsContext(ctx context.Context, lf *LockFile, fileSystem fs.FS, opts ...ChecksumOption) error {
+	o := &checksumOptions{
+		concurrency: runtime.GOMAXPROCS(0),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.concurrency < 1 {
+		o.concurrency = 1
+	}
+	if o.hasher == nil {
+		o.hasher = NewNormalizedSHA1Hasher()
+	}
+
 	if lf.TrackedFiles == nil {
 		return nil
 	}
 
-	for path := range lf.TrackedFiles.Keys() {
-		tf, ok := lf.TrackedFiles.Get(path)
-		if !ok {
-			continue
+	var paths []string
+	for path, tf := range lf.TrackedFiles.All() {
+		if tf.LastWriteChecksum == "" {
+			paths = append(paths, path)
 		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
 
-		if tf.LastWriteChecksum == "" {
-			checksum, err := ComputeFileChecksum(fileSystem, path)
-			if err != nil {
-				continue
+	type result struct {
+		index    int
+		checksum string
+		err      error
+	}
+
+	jobs := make(chan int)
+	results := make(chan result)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for range min(o.concurrency, len(paths)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				checksum, err := computeFileChecksum(fileSystem, paths[i], o.hasher)
+				select {
+				case results <- result{index: i, checksum: checksum, err: err}:
+				case <-ctx.Done():
+					return
+				}
 			}
-			tf.LastWriteChecksum = checksum
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for i := range paths {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	errs := make([]error, len(paths))
+	done := 0
+	for res := range results {
+		done++
+		path := paths[res.index]
+
+		if res.err != nil {
+			errs[res.index] = res.err
+		} else if tf, ok := lf.TrackedFiles.Get(path); ok {
+			tf.LastWriteChecksum = res.checksum
 			lf.TrackedFiles.Set(path, tf)
 		}
+
+		if o.progress != nil {
+			o.progress(ChecksumProgress{Path: path, Err: res.err, Done: done, Total: len(paths)})
+		}
+	}
+
+	if err := ctx.Err(); err != nil && done < len(paths) {
+		return fmt.Errorf("computed %d of %d checksums: %w", done, len(paths), err)
+	}
+
+	summary := &ChecksumErrors{Total: len(paths)}
+	for i, err := range errs {
+		if err != nil {
+			summary.Errors = append(summary.Errors, ChecksumError{Path: paths[i], Err: err})
+		}
 	}
+	if len(summary.Errors) > 0 {
+		return summary
+	}
+
 	return nil
 }
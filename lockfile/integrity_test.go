@@ -0,0 +1,162 @@
+package lockfile_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/speakeasy-api/openapi/sequencedmap"
+	"github.com/speakeasy-api/sdk-gen-config/lockfile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTrackedLockFile(paths ...string) *lockfile.LockFile {
+	lf := &lockfile.LockFile{TrackedFiles: sequencedmap.New[string, lockfile.TrackedFile]()}
+	for _, p := range paths {
+		lf.TrackedFiles.Set(p, lockfile.TrackedFile{ID: p})
+	}
+	return lf
+}
+
+func TestPopulateMissingChecksumsContext(t *testing.T) {
+	fsys := fstest.MapFS{}
+	var paths []string
+	for i := 0; i < 2000; i++ {
+		p := fmt.Sprintf("src/models/file%d.ts", i)
+		fsys[p] = &fstest.MapFile{Data: []byte(fmt.Sprintf("export const value = %d;\r\n", i))}
+		paths = append(paths, p)
+	}
+
+	lf := newTrackedLockFile(append(paths, "src/missing.ts", "src/also-missing.ts")...)
+	lf.TrackedFiles.Set("src/models/file0.ts", lockfile.TrackedFile{ID: "src/models/file0.ts", LastWriteChecksum: "sha1:existing"})
+
+	var progress []lockfile.ChecksumProgress
+	err := lockfile.PopulateMissingChecksumsContext(context.Background(), lf, fsys,
+		lockfile.WithConcurrency(8),
+		lockfile.WithHasher(lockfile.NewNormalizedSHA1Hasher()),
+		lockfile.WithProgress(func(p lockfile.ChecksumProgress) {
+			progress = append(progress, p)
+		}),
+	)
+
+	var checksumErrs *lockfile.ChecksumErrors
+	require.ErrorAs(t, err, &checksumErrs)
+	assert.Equal(t, 2001, checksumErrs.Total)
+	require.Len(t, checksumErrs.Errors, 2)
+	assert.Equal(t, "src/missing.ts", checksumErrs.Errors[0].Path)
+	assert.Equal(t, "src/also-missing.ts", checksumErrs.Errors[1].Path)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.Contains(t, err.Error(), "failed to compute checksums for 2 of 2001 tracked files")
+
+	require.Len(t, progress, 2001)
+	for i, p := range progress {
+		assert.Equal(t, i+1, p.Done)
+		assert.Equal(t, 2001, p.Total)
+	}
+
+	tf, _ := lf.TrackedFiles.Get("src/models/file0.ts")
+	assert.Equal(t, "sha1:existing", tf.LastWriteChecksum)
+	for _, p := range paths[1:] {
+		want, err := lockfile.ComputeFileChecksum(fsys, p)
+		require.NoError(t, err)
+		tf, _ := lf.TrackedFiles.Get(p)
+		require.Equal(t, want, tf.LastWriteChecksum, p)
+	}
+	tf, _ = lf.TrackedFiles.Get("src/missing.ts")
+	assert.Empty(t, tf.LastWriteChecksum)
+
+	// Nothing left to do
+	assert.NoError(t, lockfile.PopulateMissingChecksumsContext(context.Background(), newTrackedLockFile(), fsys))
+}
+
+func TestPopulateMissingChecksumsContext_Cancelled(t *testing.T) {
+	fsys := fstest.MapFS{}
+	var paths []string
+	for i := 0; i < 100; i++ {
+		p := fmt.Sprintf("file%d.ts", i)
+		fsys[p] = &fstest.MapFile{Data: []byte(p)}
+		paths = append(paths, p)
+	}
+	lf := newTrackedLockFile(paths...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := lockfile.PopulateMissingChecksumsContext(ctx, lf, fsys,
+		lockfile.WithConcurrency(2),
+		lockfile.WithProgress(func(p lockfile.ChecksumProgress) {
+			if p.Done == 10 {
+				cancel()
+			}
+		}),
+	)
+	require.ErrorIs(t, err, context.Canceled)
+
+	populated := 0
+	for _, tf := range lf.TrackedFiles.All() {
+		if tf.LastWriteChecksum != "" {
+			populated++
+		}
+	}
+	assert.GreaterOrEqual(t, populated, 10)
+	assert.Less(t, populated, 100)
+	assert.False(t, errors.As(err, new(*lockfile.ChecksumErrors)))
+}
+
+// concurrencyFS records the most files open at once.
+type concurrencyFS struct {
+	fs.FS
+	open, max atomic.Int32
+}
+
+func (c *concurrencyFS) Open(name string) (fs.File, error) {
+	f, err := c.FS.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	n := c.open.Add(1)
+	for {
+		m := c.max.Load()
+		if n <= m || c.max.CompareAndSwap(m, n) {
+			break
+		}
+	}
+	// Keep the file open long enough for concurrent opens to overlap
+	time.Sleep(100 * time.Microsecond)
+	return &concurrencyFile{File: f, fsys: c}, nil
+}
+
+type concurrencyFile struct {
+	fs.File
+	fsys *concurrencyFS
+}
+
+func (f *concurrencyFile) Close() error {
+	f.fsys.open.Add(-1)
+	return f.File.Close()
+}
+
+func TestPopulateMissingChecksums_Serial(t *testing.T) {
+	mapFS := fstest.MapFS{}
+	var paths []string
+	for i := 0; i < 200; i++ {
+		p := fmt.Sprintf("file%d.ts", i)
+		mapFS[p] = &fstest.MapFile{Data: []byte(p)}
+		paths = append(paths, p)
+	}
+	lf := newTrackedLockFile(append(paths, "missing.ts")...)
+
+	// Callers may pass file systems that aren't safe for concurrent use
+	fsys := &concurrencyFS{FS: mapFS}
+	require.NoError(t, lockfile.PopulateMissingChecksums(lf, fsys))
+	assert.Equal(t, int32(1), fsys.max.Load())
+
+	for _, p := range paths {
+		tf, _ := lf.TrackedFiles.Get(p)
+		assert.NotEmpty(t, tf.LastWriteChecksum, p)
+	}
+}
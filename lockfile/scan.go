@@ -0,0 +1,291 @@
+package lockfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// FileStatus is the state of a tracked file on disk compared to gen.lock.
+type FileStatus string
+
+const (
+	// FileUnchanged is on disk as it was last written
+	FileUnchanged FileStatus = "unchanged"
+	// FileModified is on disk but has been changed since it was last written
+	FileModified FileStatus = "modified"
+	// FileDeleted is no longer on disk
+	FileDeleted FileStatus = "deleted"
+	// FileMoved is no longer at its tracked path but was found at another path
+	FileMoved FileStatus = "moved"
+	// FileUnknown is on disk but has no last_write_checksum to compare against
+	FileUnknown FileStatus = "unknown"
+)
+
+// generatedMarkers are looked for in the header of untracked files without an @generated-id to decide if they look
+// generated.
+var generatedMarkers = [][]byte{
+	[]byte("generated by speakeasy"),
+	[]byte("do not edit"),
+}
+
+// skippedScanDirs are not searched for untracked files, in addition to hidden directories.
+var skippedScanDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+}
+
+// ScannedFile is the state of a single tracked file.
+type ScannedFile struct {
+	Path     string     `json:"path"`
+	Status   FileStatus `json:"status"`
+	MovedTo  string     `json:"movedTo,omitempty"`  // The path the file was found at, for FileMoved
+	Checksum string     `json:"checksum,omitempty"` // The checksum of the file on disk
+	Expected string     `json:"expected,omitempty"` // The last_write_checksum recorded in gen.lock
+}
+
+// ScanReport describes the drift between the tracked files in gen.lock and the files on disk.
+type ScanReport struct {
+	Files     []ScannedFile `json:"files"`     // Every tracked file, in gen.lock order
+	Untracked []string      `json:"untracked"` // Files that look generated but aren't tracked, sorted by path
+}
+
+// Status returns the paths of the tracked files with status, in gen.lock order.
+func (r *ScanReport) Status(status FileStatus) []string {
+	var paths []string
+	for _, f := range r.Files {
+		if f.Status == status {
+			paths = append(paths, f.Path)
+		}
+	}
+	return paths
+}
+
+// HasDrift returns true if any tracked file was modified, deleted or moved, or there are untracked generated files.
+func (r *ScanReport) HasDrift() bool {
+	for _, f := range r.Files {
+		switch f.Status {
+		case FileModified, FileDeleted, FileMoved:
+			return true
+		}
+	}
+	return len(r.Untracked) > 0
+}
+
+func (r *ScanReport) String() string {
+	if !r.HasDrift() {
+		return fmt.Sprintf("No drift in %d tracked files\n", len(r.Files))
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Drift in %d tracked files:\n", len(r.Files))
+	for _, f := range r.Files {
+		switch f.Status {
+		case FileModified:
+			fmt.Fprintf(&sb, "  ~ %s\n", f.Path)
+		case FileDeleted:
+			fmt.Fprintf(&sb, "  - %s\n", f.Path)
+		case FileMoved:
+			fmt.Fprintf(&sb, "  > %s -> %s\n", f.Path, f.MovedTo)
+		}
+	}
+	for _, p := range r.Untracked {
+		fmt.Fprintf(&sb, "  ? %s\n", p)
+	}
+	return sb.String()
+}
+
+// Apply records the deleted and moved files in the report on the tracked files of lf, so the generator skips
+// deleted files and writes moved files to their new path. Files found at their tracked path are no longer marked as
+// deleted or moved.
+func (r *ScanReport) Apply(lf *LockFile) {
+	if lf.TrackedFiles == nil {
+		return
+	}
+
+	for _, f := range r.Files {
+		tf, ok := lf.TrackedFiles.Get(f.Path)
+		if !ok {
+			continue
+		}
+
+		switch f.Status {
+		case FileDeleted:
+			tf.Deleted = true
+			tf.MovedTo = ""
+		case FileMoved:
+			tf.Deleted = false
+			tf.MovedTo = f.MovedTo
+		default:
+			tf.Deleted = false
+			tf.MovedTo = ""
+		}
+		lf.TrackedFiles.Set(f.Path, tf)
+	}
+}
+
+// Scan compares the tracked files in lf with the files in fileSystem, which should be rooted at the directory
+// containing the generated files (parent of .speakeasy/).
+//
+// Files are modified if their checksum differs from last_write_checksum. A missing file is moved if it was already
+// recorded as moved and is found at that path, or an untracked file has its ID in an @generated-id header or has its
+// last_write_checksum, and is deleted otherwise. Untracked files are reported if they look generated, which is decided
+// by an @generated-id header or markers such as "DO NOT EDIT" near the start of the file. Hidden directories, node_modules and vendor aren't
+// searched for untracked files.
+func Scan(fileSystem fs.FS, lf *LockFile) (*ScanReport, error) {
+	hasher := NewNormalizedSHA1Hasher()
+
+	tracked := map[string]bool{}
+	if lf.TrackedFiles != nil {
+		for p := range lf.TrackedFiles.Keys() {
+			tracked[p] = true
+		}
+	}
+
+	untracked, err := findGeneratedFiles(fileSystem, tracked)
+	if err != nil {
+		return nil, err
+	}
+
+	// Untracked files by ID and checksum, so moved files can be found by their header or contents
+	byID := map[string][]string{}
+	byChecksum := map[string][]string{}
+	for _, f := range untracked {
+		if f.id != "" {
+			byID[f.id] = append(byID[f.id], f.path)
+		}
+		checksum, err := computeFileChecksum(fileSystem, f.path, hasher)
+		if err != nil {
+			return nil, err
+		}
+		byChecksum[checksum] = append(byChecksum[checksum], f.path)
+	}
+	claimed := map[string]bool{}
+
+	report := &ScanReport{
+		Files: []ScannedFile{},
+	}
+
+	if lf.TrackedFiles != nil {
+		for p, tf := range lf.TrackedFiles.All() {
+			file := ScannedFile{Path: p, Expected: tf.LastWriteChecksum}
+
+			checksum, err := computeFileChecksum(fileSystem, p, hasher)
+			switch {
+			case err == nil:
+				file.Checksum = checksum
+				switch tf.LastWriteChecksum {
+				case "":
+					file.Status = FileUnknown
+				case checksum:
+					file.Status = FileUnchanged
+				default:
+					file.Status = FileModified
+				}
+			case errors.Is(err, fs.ErrNotExist):
+				file.Status = FileDeleted
+				if movedTo := findMovedFile(fileSystem, tf, byID, byChecksum, claimed, hasher); movedTo != "" {
+					file.Status = FileMoved
+					file.MovedTo = movedTo
+					claimed[movedTo] = true
+					file.Checksum, _ = computeFileChecksum(fileSystem, movedTo, hasher)
+				}
+			default:
+				return nil, err
+			}
+
+			report.Files = append(report.Files, file)
+		}
+	}
+
+	report.Untracked = []string{}
+	for _, f := range untracked {
+		if !claimed[f.path] {
+			report.Untracked = append(report.Untracked, f.path)
+		}
+	}
+
+	return report, nil
+}
+
+// findMovedFile returns the path a missing tracked file was moved to, or an empty string if it can't be found.
+func findMovedFile(fileSystem fs.FS, tf TrackedFile, byID, byChecksum map[string][]string, claimed map[string]bool, hasher *NormalizedSHA1Hasher) string {
+	if tf.MovedTo != "" {
+		if _, err := computeFileChecksum(fileSystem, tf.MovedTo, hasher); err == nil {
+			return tf.MovedTo
+		}
+	}
+
+	// The ID in the header follows the file even if it was edited after moving
+	if tf.ID != "" {
+		if p := unclaimed(byID[tf.ID], claimed); p != "" {
+			return p
+		}
+	}
+
+	if tf.LastWriteChecksum != "" {
+		return unclaimed(byChecksum[tf.LastWriteChecksum], claimed)
+	}
+
+	return ""
+}
+
+// unclaimed returns the only path that hasn't been claimed by another moved file, as copies can't be told apart.
+func unclaimed(paths []string, claimed map[string]bool) string {
+	var candidates []string
+	for _, p := range paths {
+		if !claimed[p] {
+			candidates = append(candidates, p)
+		}
+	}
+
+	if len(candidates) != 1 {
+		return ""
+	}
+	return candidates[0]
+}
+
+// generatedFile is an untracked file that looks generated.
+type generatedFile struct {
+	path string
+	id   string // From the @generated-id header, if any
+}
+
+// findGeneratedFiles returns the files in fileSystem that aren't tracked but look generated, sorted by path.
+func findGeneratedFiles(fileSystem fs.FS, tracked map[string]bool) ([]generatedFile, error) {
+	var files []generatedFile
+
+	err := walkFiles(fileSystem, func(p string) error {
+		if tracked[p] {
+			return nil
+		}
+
+		head, err := readHeader(fileSystem, p)
+		if err != nil {
+			return err
+		}
+
+		id, _ := ParseGeneratedID(head)
+		if id != "" || looksGenerated(head) {
+			files = append(files, generatedFile{path: p, id: id})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan for untracked files: %w", err)
+	}
+
+	return files, nil
+}
+
+func looksGenerated(head []byte) bool {
+	head = bytes.ToLower(head)
+	for _, marker := range generatedMarkers {
+		if bytes.Contains(head, marker) {
+			return true
+		}
+	}
+	return false
+}
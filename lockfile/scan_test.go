@@ -0,0 +1,100 @@
+package lockfile_test
+
+import (
+	"encoding/json"
+	"testing"
+	"testing/fstest"
+
+	"github.com/speakeasy-api/sdk-gen-config/lockfile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScan(t *testing.T) {
+	fsys := fstest.MapFS{
+		"src/unchanged.ts":          {Data: []byte("// Code generated by Speakeasy. DO NOT EDIT.\nexport const a = 1;\n")},
+		"src/modified.ts":           {Data: []byte("// Code generated by Speakeasy. DO NOT EDIT.\nexport const b = 2; // edited\n")},
+		"src/renamed/moved.ts":      {Data: []byte("// Code generated by Speakeasy. DO NOT EDIT.\nexport const c = 3;\n")},
+		"src/previously-moved.ts":   {Data: []byte("export const e = 5;\n")},
+		"src/no-checksum.ts":        {Data: []byte("export const f = 6;\n")},
+		"src/new-generated.ts":      {Data: []byte("/*\n * @generated-id: 123\n */\n")},
+		"src/user-code.ts":          {Data: []byte("export const custom = true;\n")},
+		".speakeasy/gen.lock":       {Data: []byte("lockVersion: 2.0.0\n")},
+		"node_modules/dep/index.js": {Data: []byte("// DO NOT EDIT\n")},
+	}
+
+	checksum := func(data string) string {
+		t.Helper()
+		sum, err := lockfile.ComputeFileChecksum(fstest.MapFS{"f": {Data: []byte(data)}}, "f")
+		require.NoError(t, err)
+		return sum
+	}
+
+	lf := newTrackedLockFile()
+	lf.TrackedFiles.Set("src/unchanged.ts", lockfile.TrackedFile{LastWriteChecksum: checksum("// Code generated by Speakeasy. DO NOT EDIT.\nexport const a = 1;\n")})
+	lf.TrackedFiles.Set("src/modified.ts", lockfile.TrackedFile{LastWriteChecksum: checksum("// Code generated by Speakeasy. DO NOT EDIT.\nexport const b = 2;\n")})
+	lf.TrackedFiles.Set("src/moved.ts", lockfile.TrackedFile{LastWriteChecksum: checksum("// Code generated by Speakeasy. DO NOT EDIT.\nexport const c = 3;\n")})
+	lf.TrackedFiles.Set("src/deleted.ts", lockfile.TrackedFile{LastWriteChecksum: checksum("export const d = 4;\n")})
+	lf.TrackedFiles.Set("src/old.ts", lockfile.TrackedFile{LastWriteChecksum: "sha1:stale", MovedTo: "src/previously-moved.ts"})
+	lf.TrackedFiles.Set("src/no-checksum.ts", lockfile.TrackedFile{})
+	lf.TrackedFiles.Set("src/restored.ts", lockfile.TrackedFile{Deleted: true})
+	fsys["src/restored.ts"] = &fstest.MapFile{Data: []byte("restored\n")}
+
+	report, err := lockfile.Scan(fsys, lf)
+	require.NoError(t, err)
+
+	statuses := map[string]lockfile.FileStatus{}
+	for _, f := range report.Files {
+		statuses[f.Path] = f.Status
+	}
+	assert.Equal(t, map[string]lockfile.FileStatus{
+		"src/unchanged.ts":   lockfile.FileUnchanged,
+		"src/modified.ts":    lockfile.FileModified,
+		"src/moved.ts":       lockfile.FileMoved,
+		"src/deleted.ts":     lockfile.FileDeleted,
+		"src/old.ts":         lockfile.FileMoved,
+		"src/no-checksum.ts": lockfile.FileUnknown,
+		"src/restored.ts":    lockfile.FileUnknown,
+	}, statuses)
+
+	assert.Equal(t, "src/renamed/moved.ts", report.Files[2].MovedTo)
+	assert.Equal(t, "src/previously-moved.ts", report.Files[4].MovedTo)
+	assert.Equal(t, []string{"src/modified.ts"}, report.Status(lockfile.FileModified))
+	assert.Equal(t, []string{"src/new-generated.ts"}, report.Untracked)
+	assert.True(t, report.HasDrift())
+
+	assert.Equal(t, `Drift in 7 tracked files:
+  ~ src/modified.ts
+  > src/moved.ts -> src/renamed/moved.ts
+  - src/deleted.ts
+  > src/old.ts -> src/previously-moved.ts
+  ? src/new-generated.ts
+`, report.String())
+
+	data, err := json.Marshal(report)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `{"path":"src/moved.ts","status":"moved","movedTo":"src/renamed/moved.ts"`)
+
+	report.Apply(lf)
+	moved, _ := lf.TrackedFiles.Get("src/moved.ts")
+	assert.Equal(t, "src/renamed/moved.ts", moved.MovedTo)
+	deleted, _ := lf.TrackedFiles.Get("src/deleted.ts")
+	assert.True(t, deleted.Deleted)
+	restored, _ := lf.TrackedFiles.Get("src/restored.ts")
+	assert.False(t, restored.Deleted)
+}
+
+func TestScan_NoDrift(t *testing.T) {
+	fsys := fstest.MapFS{
+		"README.md": {Data: []byte("# SDK\n")},
+	}
+	lf := newTrackedLockFile()
+	sum, err := lockfile.ComputeFileChecksum(fsys, "README.md")
+	require.NoError(t, err)
+	lf.TrackedFiles.Set("README.md", lockfile.TrackedFile{LastWriteChecksum: sum})
+
+	report, err := lockfile.Scan(fsys, lf)
+	require.NoError(t, err)
+	assert.False(t, report.HasDrift())
+	assert.Equal(t, "No drift in 1 tracked files\n", report.String())
+}
@@ -0,0 +1,2 @@
+// @generated-id: user
+export type User = { name: string };
@@ -0,0 +1,486 @@
+// Package memfs provides an in-memory filesystem that satisfies config.FS and workspace.FS.
+//
+// A FS can be layered over a directory on disk, in which case reads fall through to the directory until a file is
+// written or removed, and nothing is ever written back to disk. Writes are recorded and the state of the filesystem
+// can be snapshotted and diffed, which allows config.Load and friends to be previewed without touching disk.
+//
+// Both absolute paths and paths relative to the root of the FS are accepted, so the same FS can be passed to
+// config.Load, which works with absolute paths, and lockfile.PopulateMissingChecksums, which works with paths
+// relative to the SDK.
+package memfs
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FS is an in-memory filesystem, optionally overlaying a base filesystem. It is safe for concurrent use.
+type FS struct {
+	mu      sync.RWMutex
+	root    string
+	base    fs.FS
+	files   map[string]*memFile
+	removed map[string]bool
+	writes  []Write
+}
+
+type memFile struct {
+	data    []byte
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+// Write is a single call to WriteFile recorded by the FS.
+type Write struct {
+	Name string // The path of the file relative to the root of the FS, using forward slashes
+	Data []byte
+	Perm fs.FileMode
+}
+
+// New returns an empty FS rooted at the filesystem root, so absolute paths can be used as is.
+func New() *FS {
+	return &FS{
+		root:    string(filepath.Separator),
+		files:   map[string]*memFile{},
+		removed: map[string]bool{},
+	}
+}
+
+// NewOverlay returns a FS that reads from the directory at root until files are written or removed.
+// Changes are only held in memory.
+func NewOverlay(root string) (*FS, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	f := New()
+	f.root = abs
+	f.base = os.DirFS(abs)
+	return f, nil
+}
+
+// resolve converts name to a path relative to the root of the FS. Relative names must be valid as described by
+// fs.ValidPath, absolute names must be within the root.
+func (f *FS) resolve(op, name string) (string, error) {
+	if !filepath.IsAbs(name) {
+		p := filepath.ToSlash(name)
+		if !fs.ValidPath(p) {
+			return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+		}
+		return p, nil
+	}
+
+	rel, err := filepath.Rel(f.root, name)
+	if err != nil {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+
+	p := filepath.ToSlash(rel)
+	if p == ".." || strings.HasPrefix(p, "../") {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+
+	return p, nil
+}
+
+// WriteFile writes data to the named file in memory, creating it if necessary.
+func (f *FS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	p, err := f.resolve("write", name)
+	if err != nil {
+		return err
+	}
+	if p == "." {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrInvalid}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.files[p] = &memFile{
+		data:    bytes.Clone(data),
+		mode:    perm.Perm(),
+		modTime: time.Now(),
+	}
+	delete(f.removed, p)
+	f.writes = append(f.writes, Write{Name: p, Data: bytes.Clone(data), Perm: perm.Perm()})
+
+	return nil
+}
+
+// Remove removes the named file, hiding it in the base filesystem if there is one.
+func (f *FS) Remove(name string) error {
+	p, err := f.resolve("remove", name)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.files[p]; !ok && !f.inBaseLocked(p) {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+
+	delete(f.files, p)
+	if f.base != nil {
+		f.removed[p] = true
+	}
+
+	return nil
+}
+
+// ReadFile returns the contents of the named file.
+func (f *FS) ReadFile(name string) ([]byte, error) {
+	p, err := f.resolve("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if mf, ok := f.files[p]; ok {
+		return bytes.Clone(mf.data), nil
+	}
+	if f.base == nil || f.hiddenLocked(p) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return fs.ReadFile(f.base, p)
+}
+
+// Stat returns a FileInfo describing the named file or directory.
+func (f *FS) Stat(name string) (fs.FileInfo, error) {
+	p, err := f.resolve("stat", name)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.statLocked(name, p)
+}
+
+func (f *FS) statLocked(name, p string) (fs.FileInfo, error) {
+	if mf, ok := f.files[p]; ok {
+		return &fileInfo{name: path.Base(p), size: int64(len(mf.data)), mode: mf.mode, modTime: mf.modTime}, nil
+	}
+
+	if f.base != nil && !f.hiddenLocked(p) {
+		if info, err := fs.Stat(f.base, p); err == nil {
+			return info, nil
+		}
+	}
+
+	if f.isDirLocked(p) {
+		return &fileInfo{name: path.Base(p), mode: fs.ModeDir | 0o755}, nil
+	}
+
+	return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+// Open opens the named file or directory for reading.
+func (f *FS) Open(name string) (fs.File, error) {
+	p, err := f.resolve("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	info, err := f.statLocked(name, p)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	if info.IsDir() {
+		entries, err := f.readDirLocked(name, p)
+		if err != nil {
+			return nil, err
+		}
+		return &dir{info: info, entries: entries}, nil
+	}
+
+	if mf, ok := f.files[p]; ok {
+		return &file{info: info, Reader: bytes.NewReader(bytes.Clone(mf.data))}, nil
+	}
+
+	return f.base.Open(p)
+}
+
+// ReadDir returns the entries of the named directory, sorted by name.
+func (f *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	p, err := f.resolve("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.readDirLocked(name, p)
+}
+
+func (f *FS) readDirLocked(name, p string) ([]fs.DirEntry, error) {
+	entries := map[string]fs.DirEntry{}
+
+	found := false
+	if f.base != nil && !f.hiddenLocked(p) {
+		if baseEntries, err := fs.ReadDir(f.base, p); err == nil {
+			found = true
+			for _, e := range baseEntries {
+				if !f.removed[path.Join(p, e.Name())] {
+					entries[e.Name()] = e
+				}
+			}
+		}
+	}
+
+	for fp := range f.files {
+		child, ok := childOf(p, fp)
+		if !ok {
+			continue
+		}
+		found = true
+
+		if child == path.Base(fp) && path.Dir(fp) == p {
+			info, _ := f.statLocked(fp, fp)
+			entries[child] = fs.FileInfoToDirEntry(info)
+		} else if _, exists := entries[child]; !exists {
+			entries[child] = fs.FileInfoToDirEntry(&fileInfo{name: child, mode: fs.ModeDir | 0o755})
+		}
+	}
+
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	names := make([]string, 0, len(entries))
+	for n := range entries {
+		names = append(names, n)
+	}
+	slices.Sort(names)
+
+	out := make([]fs.DirEntry, 0, len(names))
+	for _, n := range names {
+		out = append(out, entries[n])
+	}
+	return out, nil
+}
+
+// childOf returns the name of the entry directly beneath dir that leads to p.
+func childOf(dir, p string) (string, bool) {
+	rest := p
+	if dir != "." {
+		if !strings.HasPrefix(p, dir+"/") {
+			return "", false
+		}
+		rest = p[len(dir)+1:]
+	}
+	child, _, _ := strings.Cut(rest, "/")
+	return child, true
+}
+
+func (f *FS) isDirLocked(p string) bool {
+	if p == "." {
+		return true
+	}
+	for fp := range f.files {
+		if strings.HasPrefix(fp, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// hiddenLocked returns true if p or one of its parents was removed from the base filesystem.
+func (f *FS) hiddenLocked(p string) bool {
+	for ; p != "." && p != "/"; p = path.Dir(p) {
+		if f.removed[p] {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *FS) inBaseLocked(p string) bool {
+	if f.base == nil || f.hiddenLocked(p) {
+		return false
+	}
+	_, err := fs.Stat(f.base, p)
+	return err == nil
+}
+
+// Writes returns the calls made to WriteFile, in order.
+func (f *FS) Writes() []Write {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return slices.Clone(f.writes)
+}
+
+// Snapshot is the state of the files written to or removed from a FS at a point in time.
+type Snapshot struct {
+	files   map[string][]byte
+	removed map[string]bool
+}
+
+// Snapshot records the current state of the FS, for use with Diff.
+func (f *FS) Snapshot() *Snapshot {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	s := &Snapshot{
+		files:   make(map[string][]byte, len(f.files)),
+		removed: make(map[string]bool, len(f.removed)),
+	}
+	for p, mf := range f.files {
+		s.files[p] = bytes.Clone(mf.data)
+	}
+	for p := range f.removed {
+		s.removed[p] = true
+	}
+	return s
+}
+
+type ChangeKind string
+
+const (
+	ChangeAdded    ChangeKind = "added"
+	ChangeModified ChangeKind = "modified"
+	ChangeDeleted  ChangeKind = "deleted"
+)
+
+// Change is a file that differs between two states of a FS.
+type Change struct {
+	Path   string
+	Kind   ChangeKind
+	Before []byte
+	After  []byte
+}
+
+// Diff returns the files that changed since the snapshot was taken, sorted by path. A nil snapshot compares against
+// the base filesystem, which gives every change made to the FS.
+func (f *FS) Diff(since *Snapshot) []Change {
+	if since == nil {
+		since = &Snapshot{}
+	}
+	current := f.Snapshot()
+
+	paths := map[string]bool{}
+	for _, s := range []*Snapshot{since, current} {
+		for p := range s.files {
+			paths[p] = true
+		}
+		for p := range s.removed {
+			paths[p] = true
+		}
+	}
+
+	sorted := make([]string, 0, len(paths))
+	for p := range paths {
+		sorted = append(sorted, p)
+	}
+	slices.Sort(sorted)
+
+	var changes []Change
+	for _, p := range sorted {
+		before, beforeOK := f.contentAt(since, p)
+		after, afterOK := f.contentAt(current, p)
+
+		switch {
+		case !beforeOK && afterOK:
+			changes = append(changes, Change{Path: p, Kind: ChangeAdded, After: after})
+		case beforeOK && !afterOK:
+			changes = append(changes, Change{Path: p, Kind: ChangeDeleted, Before: before})
+		case beforeOK && afterOK && !bytes.Equal(before, after):
+			changes = append(changes, Change{Path: p, Kind: ChangeModified, Before: before, After: after})
+		}
+	}
+
+	return changes
+}
+
+// contentAt returns the content of p in the state described by s, falling back to the base filesystem.
+func (f *FS) contentAt(s *Snapshot, p string) ([]byte, bool) {
+	if data, ok := s.files[p]; ok {
+		return data, true
+	}
+	if f.base == nil {
+		return nil, false
+	}
+	for hp := p; hp != "."; hp = path.Dir(hp) {
+		if s.removed[hp] {
+			return nil, false
+		}
+	}
+
+	data, err := fs.ReadFile(f.base, p)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+type fileInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (i *fileInfo) Name() string       { return i.name }
+func (i *fileInfo) Size() int64        { return i.size }
+func (i *fileInfo) Mode() fs.FileMode  { return i.mode }
+func (i *fileInfo) ModTime() time.Time { return i.modTime }
+func (i *fileInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i *fileInfo) Sys() any           { return nil }
+
+type file struct {
+	*bytes.Reader
+	info fs.FileInfo
+}
+
+func (f *file) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *file) Close() error               { return nil }
+
+type dir struct {
+	info    fs.FileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *dir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *dir) Close() error               { return nil }
+
+func (d *dir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.Name(), Err: errors.New("is a directory")}
+}
+
+func (d *dir) ReadDir(n int) ([]fs.DirEntry, error) {
+	remaining := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return slices.Clone(remaining), nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(remaining) {
+		n = len(remaining)
+	}
+	d.offset += n
+	return slices.Clone(remaining[:n]), nil
+}
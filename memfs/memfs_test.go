@@ -0,0 +1,175 @@
+package memfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	config "github.com/speakeasy-api/sdk-gen-config"
+	"github.com/speakeasy-api/sdk-gen-config/lockfile"
+	"github.com/speakeasy-api/sdk-gen-config/memfs"
+	"github.com/speakeasy-api/sdk-gen-config/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFS_Compliance(t *testing.T) {
+	dir := t.TempDir()
+	testutils.CreateTempFile(t, filepath.Join(dir, "src"), "a.ts", "a")
+	testutils.CreateTempFile(t, filepath.Join(dir, "src"), "b.ts", "b")
+	testutils.CreateTempFile(t, dir, "README.md", "readme")
+
+	f, err := memfs.NewOverlay(dir)
+	require.NoError(t, err)
+
+	require.NoError(t, f.WriteFile("src/c.ts", []byte("c"), 0o644))
+	require.NoError(t, f.WriteFile(filepath.Join(dir, "docs", "models", "d.md"), []byte("d"), 0o644))
+	require.NoError(t, f.Remove("src/b.ts"))
+
+	assert.NoError(t, fstest.TestFS(f, "README.md", "src/a.ts", "src/c.ts", "docs/models/d.md"))
+
+	_, err = f.Stat("src/b.ts")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestFS_Overlay(t *testing.T) {
+	dir := t.TempDir()
+	testutils.CreateTempFile(t, dir, "existing.txt", "on disk")
+
+	f, err := memfs.NewOverlay(dir)
+	require.NoError(t, err)
+
+	data, err := f.ReadFile(filepath.Join(dir, "existing.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "on disk", string(data))
+
+	before := f.Snapshot()
+
+	require.NoError(t, f.WriteFile(filepath.Join(dir, "existing.txt"), []byte("in memory"), 0o644))
+	require.NoError(t, f.WriteFile("new.txt", []byte("new"), 0o600))
+	require.NoError(t, f.WriteFile("new.txt", []byte("newer"), 0o600))
+
+	data, err = f.ReadFile("existing.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "in memory", string(data))
+
+	info, err := f.Stat("new.txt")
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode())
+	assert.Equal(t, int64(5), info.Size())
+
+	// Nothing reaches the disk
+	data, err = os.ReadFile(filepath.Join(dir, "existing.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "on disk", string(data))
+	_, err = os.Stat(filepath.Join(dir, "new.txt"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	// Paths outside the root don't exist
+	_, err = f.Stat(filepath.Join(filepath.Dir(dir), "other"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	_, err = f.ReadFile("../other")
+	assert.ErrorIs(t, err, os.ErrInvalid)
+
+	assert.Equal(t, []memfs.Write{
+		{Name: "existing.txt", Data: []byte("in memory"), Perm: 0o644},
+		{Name: "new.txt", Data: []byte("new"), Perm: 0o600},
+		{Name: "new.txt", Data: []byte("newer"), Perm: 0o600},
+	}, f.Writes())
+
+	assert.Equal(t, []memfs.Change{
+		{Path: "existing.txt", Kind: memfs.ChangeModified, Before: []byte("on disk"), After: []byte("in memory")},
+		{Path: "new.txt", Kind: memfs.ChangeAdded, After: []byte("newer")},
+	}, f.Diff(before))
+
+	after := f.Snapshot()
+	require.NoError(t, f.Remove("existing.txt"))
+	require.NoError(t, f.WriteFile("new.txt", []byte("newer"), 0o600))
+
+	assert.Equal(t, []memfs.Change{
+		{Path: "existing.txt", Kind: memfs.ChangeDeleted, Before: []byte("in memory")},
+	}, f.Diff(after))
+
+	assert.Equal(t, []memfs.Change{
+		{Path: "existing.txt", Kind: memfs.ChangeDeleted, Before: []byte("on disk")},
+		{Path: "new.txt", Kind: memfs.ChangeAdded, After: []byte("newer")},
+	}, f.Diff(nil))
+
+	assert.ErrorIs(t, f.Remove("existing.txt"), os.ErrNotExist)
+}
+
+func TestFS_New(t *testing.T) {
+	f := memfs.New()
+
+	require.NoError(t, f.WriteFile("/sdk/.speakeasy/gen.yaml", []byte("configVersion: 2.0.0\n"), 0o644))
+
+	data, err := f.ReadFile("sdk/.speakeasy/gen.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "configVersion: 2.0.0\n", string(data))
+
+	info, err := f.Stat("/sdk/.speakeasy")
+	require.NoError(t, err)
+	assert.True(t, info.IsDir())
+
+	entries, err := f.ReadDir("/")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "sdk", entries[0].Name())
+	assert.True(t, entries[0].IsDir())
+
+	assert.ErrorIs(t, f.Remove("/sdk/missing.yaml"), os.ErrNotExist)
+}
+
+func TestLoad_InMemory(t *testing.T) {
+	dir := t.TempDir()
+	speakeasyDir := filepath.Join(dir, ".speakeasy")
+	testutils.CreateTempFile(t, dir, "README.md", "# SDK\n")
+	testutils.CreateTempFile(t, speakeasyDir, "gen.yaml", `configVersion: 2.0.0
+generation:
+  sdkClassName: SDK
+go:
+  version: 1.0.0
+  packageName: openapi
+`)
+	testutils.CreateTempFile(t, speakeasyDir, "gen.lock", `lockVersion: 2.0.0
+id: 0f8fad5b-d9cb-469f-a165-70867728950e
+management:
+  releaseVersion: 1.0.0
+trackedFiles:
+  README.md:
+    id: readme
+`)
+
+	f, err := memfs.NewOverlay(dir)
+	require.NoError(t, err)
+
+	cfg, err := config.Load(dir, config.WithFileSystem(f), config.WithUpgradeFunc(func(lang, template, oldVersion, newVersion string, cfg map[string]any) (map[string]any, error) {
+		return cfg, nil
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "openapi", cfg.Config.Languages["go"].Cfg["packageName"])
+
+	readme, ok := cfg.LockFile.TrackedFiles.Get("README.md")
+	require.True(t, ok)
+	checksum, err := lockfile.ComputeFileChecksum(os.DirFS(dir), "README.md")
+	require.NoError(t, err)
+	assert.Equal(t, checksum, readme.LastWriteChecksum)
+
+	require.NoError(t, config.SaveLockFile(dir, cfg.LockFile, config.WithFileSystem(f)))
+
+	// Load fills in defaults in gen.yaml and the lock file gains the checksum, but only in memory
+	changes := f.Diff(nil)
+	require.Len(t, changes, 2)
+	assert.Equal(t, ".speakeasy/gen.lock", changes[0].Path)
+	assert.Contains(t, string(changes[0].After), "last_write_checksum: "+checksum)
+	assert.Equal(t, ".speakeasy/gen.yaml", changes[1].Path)
+	assert.Contains(t, string(changes[1].After), "versioningStrategy: automatic")
+
+	data, err := os.ReadFile(filepath.Join(speakeasyDir, "gen.lock"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "last_write_checksum")
+	data, err = os.ReadFile(filepath.Join(speakeasyDir, "gen.yaml"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "versioningStrategy")
+}
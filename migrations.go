@@ -0,0 +1,161 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var ErrMigrationExists = errors.New("migration already registered")
+
+// MigrateFunc transforms the raw gen.yaml and gen.lock documents from one configVersion to another.
+// lockFile is nil if no gen.lock exists yet, the returned lockfile replaces the existing one.
+type MigrateFunc func(cfg map[string]any, lockFile map[string]any, uf UpgradeFunc) (map[string]any, map[string]any, error)
+
+// Migration is a single step that upgrades gen.yaml and gen.lock between two config versions.
+// A From of "" represents configs that predate the configVersion key.
+type Migration struct {
+	From    string
+	To      string
+	Migrate MigrateFunc
+}
+
+// MigrationRegistry holds the migrations available to upgrade configs and plans paths between versions.
+type MigrationRegistry struct {
+	mu         sync.RWMutex
+	migrations []Migration
+}
+
+// DefaultMigrations is the registry used by Load unless WithMigrationRegistry is provided.
+// It contains the built in migrations up to the current Version.
+var DefaultMigrations = NewMigrationRegistry(builtinMigrations()...)
+
+// RegisterMigration adds a migration to the DefaultMigrations registry.
+func RegisterMigration(m Migration) error {
+	return DefaultMigrations.Register(m)
+}
+
+func NewMigrationRegistry(migrations ...Migration) *MigrationRegistry {
+	r := &MigrationRegistry{}
+	for _, m := range migrations {
+		if err := r.Register(m); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// Register adds a migration to the registry, only a single migration may be registered between two versions.
+func (r *MigrationRegistry) Register(m Migration) error {
+	if m.From == m.To {
+		return fmt.Errorf("migration from %q must target a different version", m.From)
+	}
+	if m.Migrate == nil {
+		return fmt.Errorf("migration from %q to %q has no Migrate func", m.From, m.To)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.migrations {
+		if existing.From == m.From && existing.To == m.To {
+			return fmt.Errorf("%w: %q to %q", ErrMigrationExists, m.From, m.To)
+		}
+	}
+
+	r.migrations = append(r.migrations, m)
+	return nil
+}
+
+// Plan returns the shortest sequence of migrations that upgrades a config from one version to another.
+// If several paths are equally short the one using the earliest registered migrations is chosen.
+func (r *MigrationRegistry) Plan(from, to string) ([]Migration, error) {
+	if from == to {
+		return nil, nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Breadth first search so that the shortest path is found
+	prev := map[string]int{from: -1}
+	queue := []string{from}
+
+	for len(queue) > 0 {
+		version := queue[0]
+		queue = queue[1:]
+
+		for i, m := range r.migrations {
+			if m.From != version {
+				continue
+			}
+			if _, seen := prev[m.To]; seen {
+				continue
+			}
+
+			prev[m.To] = i
+
+			if m.To == to {
+				var path []Migration
+				for v := to; v != from; v = r.migrations[prev[v]].From {
+					path = append([]Migration{r.migrations[prev[v]]}, path...)
+				}
+				return path, nil
+			}
+
+			queue = append(queue, m.To)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: no migration path from %q to %q", ErrFailedUpgrade, displayVersion(from), displayVersion(to))
+}
+
+// Migrate upgrades the raw config and lockfile documents from one version to another, applying each planned migration in turn.
+func (r *MigrationRegistry) Migrate(from, to string, cfg map[string]any, lockFile map[string]any, uf UpgradeFunc) (map[string]any, map[string]any, error) {
+	path, err := r.Plan(from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, m := range path {
+		cfg, lockFile, err = m.Migrate(cfg, lockFile, uf)
+		if err != nil {
+			return nil, nil, err
+		}
+		if cfg == nil {
+			return nil, nil, fmt.Errorf("%w: migration from %q to %q returned no config", ErrFailedUpgrade, displayVersion(m.From), displayVersion(m.To))
+		}
+
+		cfg["configVersion"] = m.To
+	}
+
+	return cfg, lockFile, nil
+}
+
+func builtinMigrations() []Migration {
+	return []Migration{
+		{
+			From: "",
+			To:   v1,
+			Migrate: func(cfg, lockFile map[string]any, uf UpgradeFunc) (map[string]any, map[string]any, error) {
+				_, cfg, err := upgradeToV100(cfg, uf)
+				return cfg, lockFile, err
+			},
+		},
+		{
+			From: v1,
+			To:   v2,
+			Migrate: func(cfg, _ map[string]any, uf UpgradeFunc) (map[string]any, map[string]any, error) {
+				_, cfg, lockFile, err := upgradeToV200(cfg, uf)
+				return cfg, lockFile, err
+			},
+		},
+	}
+}
+
+func displayVersion(v string) string {
+	if v == "" {
+		return "unversioned"
+	}
+	return v
+}
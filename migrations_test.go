@@ -0,0 +1,102 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/speakeasy-api/sdk-gen-config/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func noopMigration(from, to string) Migration {
+	return Migration{
+		From: from,
+		To:   to,
+		Migrate: func(cfg, lockFile map[string]any, _ UpgradeFunc) (map[string]any, map[string]any, error) {
+			return cfg, lockFile, nil
+		},
+	}
+}
+
+func TestMigrationRegistry_Plan(t *testing.T) {
+	r := NewMigrationRegistry(
+		noopMigration("", "1.0.0"),
+		noopMigration("1.0.0", "2.0.0"),
+		noopMigration("2.0.0", "2.5.0"),
+		noopMigration("2.5.0", "3.0.0"),
+		noopMigration("2.0.0", "3.0.0"),
+	)
+
+	versions := func(path []Migration) []string {
+		var out []string
+		for _, m := range path {
+			out = append(out, m.From+"->"+m.To)
+		}
+		return out
+	}
+
+	path, err := r.Plan("", "2.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"->1.0.0", "1.0.0->2.0.0"}, versions(path))
+
+	path, err = r.Plan("1.0.0", "3.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.0.0->2.0.0", "2.0.0->3.0.0"}, versions(path))
+
+	path, err = r.Plan("2.0.0", "2.0.0")
+	require.NoError(t, err)
+	assert.Empty(t, path)
+
+	_, err = r.Plan("3.0.0", "1.0.0")
+	assert.ErrorIs(t, err, ErrFailedUpgrade)
+	assert.ErrorContains(t, err, `no migration path from "3.0.0" to "1.0.0"`)
+}
+
+func TestMigrationRegistry_Register_Error(t *testing.T) {
+	r := NewMigrationRegistry(noopMigration("1.0.0", "2.0.0"))
+
+	err := r.Register(noopMigration("1.0.0", "2.0.0"))
+	assert.ErrorIs(t, err, ErrMigrationExists)
+
+	err = r.Register(noopMigration("2.0.0", "2.0.0"))
+	assert.Error(t, err)
+
+	err = r.Register(Migration{From: "2.0.0", To: "3.0.0"})
+	assert.Error(t, err)
+}
+
+func TestLoad_WithRegisteredMigration(t *testing.T) {
+	r := NewMigrationRegistry(builtinMigrations()...)
+	require.NoError(t, r.Register(Migration{
+		From: v2,
+		To:   "3.0.0",
+		Migrate: func(cfg, lockFile map[string]any, _ UpgradeFunc) (map[string]any, map[string]any, error) {
+			generation, _ := cfg["generation"].(map[string]any)
+			if className, ok := generation["sdkClassName"]; ok {
+				generation["rootClassName"] = className
+				delete(generation, "sdkClassName")
+			}
+			return cfg, lockFile, nil
+		},
+	}))
+
+	dir := t.TempDir()
+	speakeasyDir := filepath.Join(dir, ".speakeasy")
+	testutils.CreateTempFile(t, speakeasyDir, "gen.yaml", testutils.ReadTestFile(t, "v100-gen.yaml"))
+
+	cfg, err := Load(dir, WithUpgradeFunc(testUpdateLang), WithLanguages("go"), WithMigrationRegistry(r), WithTargetVersion("3.0.0"))
+	require.NoError(t, err)
+	assert.Equal(t, "3.0.0", cfg.Config.ConfigVersion)
+	assert.Equal(t, "speakeasy", cfg.Config.Generation.AdditionalProperties["rootClassName"])
+	assert.Equal(t, "1.3.0", cfg.LockFile.Management.ReleaseVersion)
+
+	data, err := os.ReadFile(filepath.Join(speakeasyDir, "gen.yaml"))
+	require.NoError(t, err)
+
+	var written map[string]any
+	require.NoError(t, yaml.Unmarshal(data, &written))
+	assert.Equal(t, "3.0.0", written["configVersion"])
+}
@@ -0,0 +1,257 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// patchYAML encodes v and merges the result into the document held in original.
+// Only the nodes whose values actually changed are replaced, so comments, key
+// order, anchors, quoting styles and blank lines in the original document
+// survive the round trip. If original is empty or can't be parsed v is encoded
+// as a fresh document.
+func patchYAML(original []byte, v any) ([]byte, error) {
+	var updated yaml.Node
+	if err := updated.Encode(v); err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if len(bytes.TrimSpace(original)) == 0 || yaml.Unmarshal(original, &doc) != nil || doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return encodeYAML(&updated)
+	}
+
+	blankLines := blankLinePaths(original, &doc)
+
+	doc.Content[0] = patchNode(doc.Content[0], &updated)
+
+	data, err := encodeYAML(&doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return restoreBlankLines(data, blankLines)
+}
+
+func encodeYAML(v any) ([]byte, error) {
+	var b bytes.Buffer
+	buf := bufio.NewWriter(&b)
+
+	e := yaml.NewEncoder(buf)
+	e.SetIndent(2)
+	if err := e.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := e.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := buf.Flush(); err != nil {
+		return nil, err
+	}
+
+	// yaml.v3 emits an explicit tag for merge keys it parsed, which is valid but noisy
+	return bytes.ReplaceAll(b.Bytes(), []byte("!!merge <<:"), []byte("<<:")), nil
+}
+
+// patchNode returns the node that should replace orig in the document so that it represents the value of updated.
+func patchNode(orig, updated *yaml.Node) *yaml.Node {
+	if nodesEqual(orig, updated) {
+		return orig
+	}
+
+	switch {
+	case orig.Kind == yaml.MappingNode && updated.Kind == yaml.MappingNode:
+		patchMapping(orig, updated)
+		return orig
+	case orig.Kind == yaml.SequenceNode && updated.Kind == yaml.SequenceNode && len(orig.Content) == len(updated.Content):
+		for i := range orig.Content {
+			orig.Content[i] = patchNode(orig.Content[i], updated.Content[i])
+		}
+		return orig
+	}
+
+	updated.HeadComment = orig.HeadComment
+	updated.LineComment = orig.LineComment
+	updated.FootComment = orig.FootComment
+	if orig.Kind == yaml.ScalarNode && updated.Kind == yaml.ScalarNode && orig.ShortTag() == updated.ShortTag() {
+		updated.Style = orig.Style
+	}
+
+	return updated
+}
+
+func patchMapping(orig, updated *yaml.Node) {
+	updatedValues := map[string]*yaml.Node{}
+	for i := 0; i+1 < len(updated.Content); i += 2 {
+		updatedValues[updated.Content[i].Value] = updated.Content[i+1]
+	}
+
+	merged := mergedValues(orig)
+
+	content := make([]*yaml.Node, 0, len(orig.Content))
+	existing := map[string]bool{}
+
+	for i := 0; i+1 < len(orig.Content); i += 2 {
+		key, value := orig.Content[i], orig.Content[i+1]
+
+		if isMergeKey(key) {
+			content = append(content, key, value)
+			continue
+		}
+
+		updatedValue, ok := updatedValues[key.Value]
+		if !ok {
+			// The key was removed
+			continue
+		}
+
+		existing[key.Value] = true
+		content = append(content, key, patchNode(value, updatedValue))
+	}
+
+	for i := 0; i+1 < len(updated.Content); i += 2 {
+		key, value := updated.Content[i], updated.Content[i+1]
+		if existing[key.Value] {
+			continue
+		}
+
+		// Values provided by a merge key don't need repeating if they didn't change
+		if mergedValue, ok := merged[key.Value]; ok && nodesEqual(mergedValue, value) {
+			continue
+		}
+
+		content = append(content, key, value)
+	}
+
+	orig.Content = content
+}
+
+// mergedValues returns the values a mapping node inherits through << merge keys.
+func mergedValues(node *yaml.Node) map[string]*yaml.Node {
+	values := map[string]*yaml.Node{}
+
+	var collect func(n *yaml.Node)
+	collect = func(n *yaml.Node) {
+		switch n.Kind {
+		case yaml.AliasNode:
+			if n.Alias != nil {
+				collect(n.Alias)
+			}
+		case yaml.SequenceNode:
+			for _, c := range n.Content {
+				collect(c)
+			}
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				if _, ok := values[n.Content[i].Value]; !ok {
+					values[n.Content[i].Value] = n.Content[i+1]
+				}
+			}
+		}
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if isMergeKey(node.Content[i]) {
+			collect(node.Content[i+1])
+		}
+	}
+
+	return values
+}
+
+func isMergeKey(n *yaml.Node) bool {
+	return n.Kind == yaml.ScalarNode && n.Value == "<<" && (n.Tag == "" || n.ShortTag() == "!!merge")
+}
+
+func nodesEqual(a, b *yaml.Node) bool {
+	var av, bv any
+	if err := a.Decode(&av); err != nil {
+		return false
+	}
+	if err := b.Decode(&bv); err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(av, bv)
+}
+
+// blankLinePaths records the mapping keys in the document that are preceded by a blank line.
+func blankLinePaths(src []byte, doc *yaml.Node) map[string]bool {
+	lines := strings.Split(string(src), "\n")
+
+	paths := map[string]bool{}
+	walkMappingKeys(doc, "", func(path string, key *yaml.Node) {
+		start := keyStartLine(key)
+		if start >= 2 && start-2 < len(lines) && strings.TrimSpace(lines[start-2]) == "" {
+			paths[path] = true
+		}
+	})
+
+	return paths
+}
+
+// restoreBlankLines re-inserts the blank lines that the encoder drops before the keys recorded by blankLinePaths.
+func restoreBlankLines(data []byte, paths map[string]bool) ([]byte, error) {
+	if len(paths) == 0 {
+		return data, nil
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse patched document: %w", err)
+	}
+
+	insertBefore := map[int]bool{}
+	walkMappingKeys(&doc, "", func(path string, key *yaml.Node) {
+		if paths[path] {
+			insertBefore[keyStartLine(key)] = true
+		}
+	})
+
+	lines := strings.SplitAfter(string(data), "\n")
+
+	var out strings.Builder
+	for i, line := range lines {
+		if insertBefore[i+1] && i > 0 && strings.TrimSpace(lines[i-1]) != "" {
+			out.WriteString("\n")
+		}
+		out.WriteString(line)
+	}
+
+	return []byte(out.String()), nil
+}
+
+// keyStartLine returns the first line of a mapping key, including any head comment attached to it.
+func keyStartLine(key *yaml.Node) int {
+	start := key.Line
+	if key.HeadComment != "" {
+		start -= strings.Count(key.HeadComment, "\n") + 1
+	}
+	return start
+}
+
+func walkMappingKeys(n *yaml.Node, path string, fn func(path string, key *yaml.Node)) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		for _, c := range n.Content {
+			walkMappingKeys(c, path, fn)
+		}
+	case yaml.SequenceNode:
+		for i, c := range n.Content {
+			walkMappingKeys(c, path+"["+strconv.Itoa(i)+"]", fn)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			keyPath := path + "." + n.Content[i].Value
+			fn(keyPath, n.Content[i])
+			walkMappingKeys(n.Content[i+1], keyPath, fn)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/speakeasy-api/sdk-gen-config/lockfile"
+	"github.com/speakeasy-api/sdk-gen-config/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatchYAML_Success(t *testing.T) {
+	tests := []struct {
+		name     string
+		original string
+		value    any
+		want     string
+	}{
+		{
+			name:  "encodes a fresh document when there is no original",
+			value: map[string]any{"b": 1, "a": "x"},
+			want: `a: x
+b: 1
+`,
+		},
+		{
+			name: "preserves comments, blank lines and key order when nothing changed",
+			original: `# top level comment
+configVersion: 2.0.0 # the version
+
+# generation settings
+generation:
+  sdkClassName: "speakeasy"
+  baseServerUrl: https://api.example.com
+go:
+  version: 1.0.0
+`,
+			value: map[string]any{
+				"configVersion": "2.0.0",
+				"generation": map[string]any{
+					"baseServerUrl": "https://api.example.com",
+					"sdkClassName":  "speakeasy",
+				},
+				"go": map[string]any{
+					"version": "1.0.0",
+				},
+			},
+			want: `# top level comment
+configVersion: 2.0.0 # the version
+
+# generation settings
+generation:
+  sdkClassName: "speakeasy"
+  baseServerUrl: https://api.example.com
+go:
+  version: 1.0.0
+`,
+		},
+		{
+			name: "patches changed values and appends new keys",
+			original: `configVersion: 2.0.0
+generation:
+  # the class name
+  sdkClassName: "speakeasy" # inline
+  maintainOpenAPIOrder: false
+
+go:
+  version: 1.0.0
+`,
+			value: map[string]any{
+				"configVersion": "2.0.0",
+				"generation": map[string]any{
+					"sdkClassName":         "MySDK",
+					"maintainOpenAPIOrder": true,
+					"deduplicateErrors":    true,
+				},
+				"go": map[string]any{
+					"version": "1.0.1",
+				},
+			},
+			want: `configVersion: 2.0.0
+generation:
+  # the class name
+  sdkClassName: "MySDK" # inline
+  maintainOpenAPIOrder: true
+  deduplicateErrors: true
+
+go:
+  version: 1.0.1
+`,
+		},
+		{
+			name: "removes keys that are no longer present",
+			original: `configVersion: 1.0.0
+management:
+  docVersion: 0.1.0
+# generation settings
+generation:
+  sdkClassName: speakeasy
+`,
+			value: map[string]any{
+				"configVersion": "2.0.0",
+				"generation": map[string]any{
+					"sdkClassName": "speakeasy",
+				},
+			},
+			want: `configVersion: 2.0.0
+# generation settings
+generation:
+  sdkClassName: speakeasy
+`,
+		},
+		{
+			name: "keeps anchors and merge keys",
+			original: `base: &base
+  version: 1.0.0
+go:
+  <<: *base
+  packageName: openapi
+`,
+			value: map[string]any{
+				"base": map[string]any{"version": "1.0.0"},
+				"go": map[string]any{
+					"version":     "1.0.0",
+					"packageName": "sdk",
+				},
+			},
+			want: `base: &base
+  version: 1.0.0
+go:
+  <<: *base
+  packageName: sdk
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := patchYAML([]byte(tt.original), tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestLoad_PreservesCommentsWhenUpgrading(t *testing.T) {
+	getUUID = func() string {
+		return "123"
+	}
+	lockfile.GetUUID = getUUID
+
+	genYaml := `# Managed by the SDK team, please don't remove this comment
+configVersion: 2.0.0
+
+generation:
+  sdkClassName: speakeasy # the root class
+  baseServerUrl: https://api.prod.speakeasyapi.dev
+
+# Go specific settings
+go:
+  version: 1.3.0
+  packageName: github.com/speakeasy-api/speakeasy-client-sdk-go
+`
+
+	lockFile := `# lock comment
+lockVersion: 2.0.0
+id: 0f8fad5b-d9cb-469f-a165-70867728950e
+management:
+  docChecksum: 2bba3b8f9d211b02569b3f9aff0d34b4 # checksum
+`
+
+	dir := t.TempDir()
+	speakeasyDir := filepath.Join(dir, ".speakeasy")
+	testutils.CreateTempFile(t, speakeasyDir, "gen.yaml", genYaml)
+	testutils.CreateTempFile(t, speakeasyDir, "gen.lock", lockFile)
+
+	_, err := Load(dir, WithUpgradeFunc(testUpdateLang), WithLanguages("go"))
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(speakeasyDir, "gen.yaml"))
+	require.NoError(t, err)
+
+	assert.Contains(t, string(data), "# Managed by the SDK team, please don't remove this comment\nconfigVersion: 2.0.0\n\ngeneration:\n  sdkClassName: speakeasy # the root class\n  baseServerUrl: https://api.prod.speakeasyapi.dev\n")
+	assert.Contains(t, string(data), "\n# Go specific settings\ngo:\n  version: 1.3.0\n  packageName: github.com/speakeasy-api/speakeasy-client-sdk-go\n")
+
+	data, err = os.ReadFile(filepath.Join(speakeasyDir, "gen.lock"))
+	require.NoError(t, err)
+
+	assert.Contains(t, string(data), "# lock comment\nlockVersion: 2.0.0\n")
+	assert.Contains(t, string(data), "docChecksum: 2bba3b8f9d211b02569b3f9aff0d34b4 # checksum\n")
+}
@@ -0,0 +1,455 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var ErrUnknownPath = errors.New("unknown configuration path")
+
+// lookupField returns the value at a dotted path of yaml keys within v, for example "generation.fixes.securityFeb2025".
+// Unset pointers resolve to the zero value of the type they point to. Keys in inline maps resolve only if they are present.
+func lookupField(v any, path string) (any, error) {
+	if path == "" {
+		return nil, fmt.Errorf("%w: path is empty", ErrUnknownPath)
+	}
+
+	current := reflect.ValueOf(v)
+	for i, part := range strings.Split(path, ".") {
+		next, ok := childValue(current, part)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownPath, strings.Join(strings.Split(path, ".")[:i+1], "."))
+		}
+		current = next
+	}
+
+	current = indirectValue(current)
+	if !current.IsValid() {
+		return nil, nil
+	}
+	return current.Interface(), nil
+}
+
+// canonicalPath returns path with each struct field key replaced by its yaml key, as recorded when loading the config,
+// so paths that resolve case-insensitively can be matched. Paths that don't resolve are returned unchanged.
+func canonicalPath(v any, path string) string {
+	parts := strings.Split(path, ".")
+
+	current := reflect.ValueOf(v)
+	for i, part := range parts {
+		if s := indirectValue(current); s.IsValid() && s.Kind() == reflect.Struct {
+			if sf, ok := structFieldInfo(s.Type(), part); ok {
+				if name, _ := yamlFieldName(sf); name != "" {
+					parts[i] = name
+				}
+				current = s.FieldByIndex(sf.Index)
+				continue
+			}
+		}
+
+		next, ok := childValue(current, part)
+		if !ok {
+			return path
+		}
+		current = next
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// childValue returns the value stored under key in the struct or map held by v.
+func childValue(v reflect.Value, key string) (reflect.Value, bool) {
+	v = indirectValue(v)
+	if !v.IsValid() {
+		return reflect.Value{}, false
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return reflect.Value{}, false
+		}
+		child := v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key()))
+		return child, child.IsValid()
+	case reflect.Struct:
+		if field, ok := structField(v, key); ok {
+			return field, true
+		}
+
+		// Fall back to any inline maps capturing additional properties
+		for i := 0; i < v.NumField(); i++ {
+			name, inline := yamlFieldName(v.Type().Field(i))
+			if name == "" && inline && v.Field(i).Kind() == reflect.Map {
+				if child, ok := childValue(v.Field(i), key); ok {
+					return child, true
+				}
+			}
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// structField returns the field of v with the yaml key name, matching case-insensitively if there is no exact match.
+func structField(v reflect.Value, key string) (reflect.Value, bool) {
+	sf, ok := structFieldInfo(v.Type(), key)
+	if !ok {
+		return reflect.Value{}, false
+	}
+	return v.FieldByIndex(sf.Index), true
+}
+
+// yamlFieldName returns the yaml key for a struct field and whether it is inlined.
+func yamlFieldName(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("yaml")
+	if tag == "-" {
+		return "", false
+	}
+
+	name, opts, _ := strings.Cut(tag, ",")
+	inline := false
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == "inline" {
+			inline = true
+		}
+	}
+
+	if name == "" && !inline {
+		name = strings.ToLower(f.Name)
+	}
+
+	return name, inline
+}
+
+// indirectValue dereferences pointers and interfaces, using the zero value for nil pointers.
+func indirectValue(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			if v.Kind() == reflect.Pointer {
+				return reflect.Zero(v.Type().Elem())
+			}
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+var ErrInvalidValue = errors.New("invalid configuration value")
+
+// Get returns the value at a dotted path such as "generation.usageSnippets.sdkInitStyle" or "go.maxMethodParams".
+// Language options that are known but not set return nil.
+func (c *Configuration) Get(path string) (any, error) {
+	value, err := lookupField(c, path)
+	if err == nil || !errors.Is(err, ErrUnknownPath) {
+		return value, err
+	}
+
+	// Language options that aren't set are still known through the typed language configs
+	lang, rest, _ := strings.Cut(path, ".")
+	if _, ok := c.Languages[lang]; !ok || rest == "" {
+		return nil, err
+	}
+
+	typed := NewTypedLanguageConfig(lang)
+	if typed == nil {
+		return nil, err
+	}
+	if _, typedErr := lookupField(typed, rest); typedErr != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// Set updates the value at a dotted path such as "generation.usageSnippets.sdkInitStyle" or "go.maxMethodParams".
+// The value is converted to the type of the field, strings are parsed for boolean and numeric fields, and checked
+// against any enum and the ValidationRegex of the matching SDKGenConfigField. Language options are checked against
+// the typed config for the language. Setting a nil value clears the field.
+func (c *Configuration) Set(path string, value any) error {
+	parts := strings.Split(path, ".")
+	if slices.Contains(parts, "") {
+		return fmt.Errorf("%w: %q", ErrUnknownPath, path)
+	}
+
+	if _, ok := structField(reflect.ValueOf(c).Elem(), parts[0]); !ok {
+		return c.setLanguageField(parts[0], parts[1:], path, value)
+	}
+
+	if parts[0] == "generation" && len(parts) > 1 {
+		if err := validateGenerationField(strings.Join(parts[1:], "."), path, value); err != nil {
+			return err
+		}
+	}
+
+	return setField(reflect.ValueOf(c).Elem(), parts, path, value, "")
+}
+
+func (c *Configuration) setLanguageField(lang string, parts []string, path string, value any) error {
+	typed := NewTypedLanguageConfig(lang)
+	if typed == nil {
+		if _, ok := c.Languages[lang]; !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownPath, path)
+		}
+	}
+	if len(parts) == 0 {
+		return fmt.Errorf("%w: %s is a language, set one of its options", ErrUnknownPath, path)
+	}
+
+	lc := c.Languages[lang]
+
+	if parts[0] == "version" && len(parts) == 1 {
+		if err := setField(reflect.ValueOf(&lc).Elem(), parts, path, value, ""); err != nil {
+			return err
+		}
+	} else {
+		converted := value
+
+		// Type check against the typed config, then store the converted value
+		if typed != nil {
+			typedValue := reflect.ValueOf(typed).Elem()
+			if err := setField(typedValue, parts, path, value, ""); err != nil {
+				return err
+			}
+
+			var err error
+			converted, err = lookupField(typed, strings.Join(parts, "."))
+			if err != nil {
+				return err
+			}
+			if value == nil {
+				converted = nil
+			}
+		}
+
+		if lc.Cfg == nil {
+			lc.Cfg = map[string]any{}
+		}
+		if err := setField(reflect.ValueOf(lc.Cfg), parts, path, converted, ""); err != nil {
+			return err
+		}
+	}
+
+	if c.Languages == nil {
+		c.Languages = map[string]LanguageConfig{}
+	}
+	c.Languages[lang] = lc
+
+	return nil
+}
+
+// validateGenerationField checks a value against the rules of the SDKGenConfigField with the given name.
+func validateGenerationField(name, path string, value any) error {
+	if _, ok := value.(string); !ok {
+		return nil
+	}
+
+	for _, field := range GetGenerationDefaults(false) {
+		if !strings.EqualFold(field.Name, name) {
+			continue
+		}
+		if msg := validateField(field, value, false); msg != "" {
+			return fmt.Errorf("%w: %s: %s", ErrInvalidValue, path, msg)
+		}
+	}
+
+	return nil
+}
+
+// setField sets the value at parts beneath v, allocating any nil pointers and maps along the way.
+// Only declared struct fields can be set, inline maps of additional properties are not written to.
+func setField(v reflect.Value, parts []string, path string, value any, enum string) error {
+	if len(parts) == 0 {
+		return assignValue(v, path, value, enum)
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return setField(v.Elem(), parts, path, value, enum)
+	case reflect.Struct:
+		sf, ok := structFieldInfo(v.Type(), parts[0])
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownPath, path)
+		}
+
+		return setField(v.FieldByIndex(sf.Index), parts[1:], path, value, sf.Tag.Get("enum"))
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+
+		key := reflect.ValueOf(parts[0]).Convert(v.Type().Key())
+		if len(parts) == 1 && value == nil {
+			v.SetMapIndex(key, reflect.Value{})
+			return nil
+		}
+
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if existing := v.MapIndex(key); existing.IsValid() {
+			elem.Set(existing)
+		}
+		if err := setField(elem, parts[1:], path, value, ""); err != nil {
+			return err
+		}
+		v.SetMapIndex(key, elem)
+		return nil
+	case reflect.Interface:
+		m, ok := v.Interface().(map[string]any)
+		if v.IsNil() {
+			m, ok = map[string]any{}, true
+		}
+		if !ok {
+			break
+		}
+
+		if err := setField(reflect.ValueOf(m), parts, path, value, ""); err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(m))
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s: %s is not a mapping", ErrUnknownPath, path, parts[0])
+}
+
+// structFieldInfo returns the struct field with the yaml key name, matching case-insensitively if there is no exact match.
+func structFieldInfo(t reflect.Type, key string) (reflect.StructField, bool) {
+	var fold *reflect.StructField
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, inline := yamlFieldName(f)
+		if inline && f.Type.Kind() == reflect.Struct {
+			if sf, ok := structFieldInfo(f.Type, key); ok {
+				sf.Index = append(slices.Clone(f.Index), sf.Index...)
+				return sf, true
+			}
+			continue
+		}
+
+		switch {
+		case name == key:
+			return f, true
+		case name != "" && fold == nil && strings.EqualFold(name, key):
+			fold = &f
+		}
+	}
+
+	if fold == nil {
+		return reflect.StructField{}, false
+	}
+	return *fold, true
+}
+
+func assignValue(v reflect.Value, path string, value any, enum string) error {
+	if value == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	if v.Kind() == reflect.Pointer {
+		elem := reflect.New(v.Type().Elem())
+		if err := assignValue(elem.Elem(), path, value, enum); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+
+	converted, err := convertValue(reflect.ValueOf(value), v.Type())
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrInvalidValue, path, err)
+	}
+
+	if enum != "" {
+		allowed := strings.Split(enum, ",")
+		if !slices.Contains(allowed, fmt.Sprint(converted.Interface())) {
+			return fmt.Errorf("%w: %s: must be one of %s", ErrInvalidValue, path, strings.Join(allowed, ", "))
+		}
+	}
+
+	v.Set(converted)
+	return nil
+}
+
+// convertValue converts v to type t, parsing strings for boolean and numeric types.
+func convertValue(v reflect.Value, t reflect.Type) (reflect.Value, error) {
+	if v.Type().AssignableTo(t) {
+		return v, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		if v.Kind() == reflect.String {
+			return v.Convert(t), nil
+		}
+	case reflect.Bool:
+		switch v.Kind() {
+		case reflect.Bool:
+			return v.Convert(t), nil
+		case reflect.String:
+			b, err := strconv.ParseBool(v.String())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("must be a boolean, got %q", v.String())
+			}
+			return reflect.ValueOf(b).Convert(t), nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return v.Convert(t), nil
+		case reflect.Float32, reflect.Float64:
+			if f := v.Float(); f == float64(int64(f)) {
+				return reflect.ValueOf(int64(f)).Convert(t), nil
+			}
+		case reflect.String:
+			i, err := strconv.ParseInt(v.String(), 10, 64)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("must be an integer, got %q", v.String())
+			}
+			return reflect.ValueOf(i).Convert(t), nil
+		}
+		return reflect.Value{}, fmt.Errorf("must be an integer, got %v", v.Interface())
+	case reflect.Float32, reflect.Float64:
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Float32, reflect.Float64:
+			return v.Convert(t), nil
+		case reflect.String:
+			f, err := strconv.ParseFloat(v.String(), 64)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("must be a number, got %q", v.String())
+			}
+			return reflect.ValueOf(f).Convert(t), nil
+		}
+		return reflect.Value{}, fmt.Errorf("must be a number, got %v", v.Interface())
+	case reflect.Slice, reflect.Map, reflect.Struct:
+		// Composite values are converted through their yaml representation
+		data, err := yaml.Marshal(v.Interface())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out := reflect.New(t)
+		if err := yaml.Unmarshal(data, out.Interface()); err != nil {
+			return reflect.Value{}, fmt.Errorf("must be a %s", t.Kind())
+		}
+		return out.Elem(), nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("must be a %s, got %T", t.Kind(), v.Interface())
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfiguration_Get(t *testing.T) {
+	cfg, err := GetDefaultConfig(true, nil, map[string]bool{"go": true})
+	require.NoError(t, err)
+	require.NoError(t, cfg.Set("go.packageName", "openapi"))
+
+	tests := []struct {
+		path    string
+		want    any
+		wantErr error
+	}{
+		{path: "configVersion", want: Version},
+		{path: "generation.sdkClassName", want: "SDK"},
+		{path: "generation.baseServerURL", want: ""},
+		{path: "generation.usageSnippets.sdkInitStyle", want: SDKInitStyleConstructor},
+		{path: "generation.fixes.nameResolutionFeb2025", want: true},
+		{path: "generation.mockServer.disabled", want: false},
+		{path: "go.version", want: "0.0.1"},
+		{path: "go.packageName", want: "openapi"},
+		{path: "go.maxMethodParams", want: nil},
+		{path: "generation.fixes.unknown", wantErr: ErrUnknownPath},
+		{path: "go.unknown", wantErr: ErrUnknownPath},
+		{path: "typescript.version", wantErr: ErrUnknownPath},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := cfg.Get(tt.path)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConfiguration_Set_Success(t *testing.T) {
+	tests := []struct {
+		path  string
+		value any
+		want  any
+	}{
+		{path: "generation.sdkClassName", value: "MySDK", want: "MySDK"},
+		{path: "generation.baseServerUrl", value: "https://api.example.com", want: "https://api.example.com"},
+		{path: "generation.baseServerURL", value: "", want: ""},
+		{path: "generation.maintainOpenAPIOrder", value: "true", want: true},
+		{path: "generation.usageSnippets.sdkInitStyle", value: "builder", want: SDKInitStyleBuilder},
+		{path: "generation.mockServer.disabled", value: true, want: true},
+		{path: "generation.persistentEdits.enabled", value: "never", want: PersistentEditsEnabledNever},
+		{path: "go.version", value: "1.2.3", want: "1.2.3"},
+		{path: "go.maxMethodParams", value: "4", want: 4.0},
+		{path: "go.flattenGlobalSecurity", value: false, want: false},
+		{path: "typescript.packageName", value: "@speakeasy/sdk", want: "@speakeasy/sdk"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			cfg, err := GetDefaultConfig(false, nil, map[string]bool{"go": false})
+			require.NoError(t, err)
+
+			require.NoError(t, cfg.Set(tt.path, tt.value))
+
+			got, err := cfg.Get(tt.path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConfiguration_Set_Error(t *testing.T) {
+	tests := []struct {
+		path    string
+		value   any
+		wantErr error
+		wantMsg string
+	}{
+		{path: "generation.sdkClassName", value: "My SDK", wantErr: ErrInvalidValue, wantMsg: "invalid configuration value: generation.sdkClassName: Letters, numbers, or .-_ only"},
+		{path: "generation.baseServerUrl", value: "localhost", wantErr: ErrInvalidValue, wantMsg: "invalid configuration value: generation.baseServerUrl: Must be a valid server URL"},
+		{path: "generation.maintainOpenAPIOrder", value: "yes", wantErr: ErrInvalidValue},
+		{path: "generation.usageSnippets.sdkInitStyle", value: "factory", wantErr: ErrInvalidValue, wantMsg: "invalid configuration value: generation.usageSnippets.sdkInitStyle: must be one of constructor, builder"},
+		{path: "generation.sdkClassName.nested", value: "x", wantErr: ErrUnknownPath},
+		{path: "generation.unknown", value: true, wantErr: ErrUnknownPath},
+		{path: "go.maxMethodParams", value: "lots", wantErr: ErrInvalidValue},
+		{path: "go.unknown", value: true, wantErr: ErrUnknownPath},
+		{path: "notALanguage.version", value: "1.0.0", wantErr: ErrUnknownPath},
+		{path: "go", value: "1.0.0", wantErr: ErrUnknownPath},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			cfg, err := GetDefaultConfig(false, nil, map[string]bool{"go": false})
+			require.NoError(t, err)
+
+			err = cfg.Set(tt.path, tt.value)
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantMsg != "" {
+				assert.EqualError(t, err, tt.wantMsg)
+			}
+		})
+	}
+}
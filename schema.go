@@ -0,0 +1,414 @@
+package config
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed schemas/gen.config.schema.json schemas/languages/*.schema.json
+var schemaFS embed.FS
+
+const (
+	schemaBaseURL   = "file:///schemas/"
+	configSchemaURL = schemaBaseURL + "gen.config.schema.json"
+)
+
+var (
+	compileConfigSchemaOnce sync.Once
+	configSchema            *jsonschema.Schema
+	configSchemaDocs        map[string]any
+	configSchemaErr         error
+)
+
+// SchemaDiagnostic describes a single violation of the gen.yaml schema.
+type SchemaDiagnostic struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Pointer string `json:"pointer"` // JSON pointer to the offending value within the document
+	Message string `json:"message"`
+}
+
+func (d SchemaDiagnostic) String() string {
+	pointer := d.Pointer
+	if pointer == "" {
+		pointer = "/"
+	}
+	return fmt.Sprintf("%s:%d:%d: %s: %s", d.File, d.Line, d.Column, pointer, d.Message)
+}
+
+// SchemaValidationError is returned when a gen.yaml document doesn't conform to the schema.
+type SchemaValidationError struct {
+	Diagnostics []SchemaDiagnostic
+}
+
+func (e *SchemaValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Diagnostics))
+	for _, d := range e.Diagnostics {
+		msgs = append(msgs, d.String())
+	}
+	return fmt.Sprintf("gen.yaml failed schema validation:\n%s", strings.Join(msgs, "\n"))
+}
+
+// ValidateConfigSchema validates the raw contents of a gen.yaml file against the embedded
+// gen.config.schema.json and language schemas. All violations are returned as a *SchemaValidationError.
+// Unknown keys are allowed wherever the schema allows them, but keys that look like a misspelling of a
+// known key (for example maintainOpenApiOrder instead of maintainOpenAPIOrder) are reported.
+func ValidateConfigSchema(file string, data []byte) error {
+	diagnostics, err := configSchemaDiagnostics(file, data, false)
+	if err != nil {
+		return err
+	}
+	if len(diagnostics) == 0 {
+		return nil
+	}
+	return &SchemaValidationError{Diagnostics: diagnostics}
+}
+
+// configSchemaDiagnostics returns the schema violations in data, sorted by position. Partial configs, such as
+// gen.local.yaml or the bases listed in extends, only hold some of the keys so missing required keys aren't reported.
+func configSchemaDiagnostics(file string, data []byte, partial bool) ([]SchemaDiagnostic, error) {
+	sch, docs, err := compileConfigSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("could not unmarshal %s: %w", file, err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil, nil
+	}
+
+	locs := map[string]nodeLocation{}
+	instance := nodeToInstance(doc.Content[0], nil, "", locs)
+
+	var diagnostics []SchemaDiagnostic
+
+	if err := sch.Validate(instance); err != nil {
+		var ve *jsonschema.ValidationError
+		if !errors.As(err, &ve) {
+			return nil, fmt.Errorf("could not validate %s: %w", file, err)
+		}
+
+		for _, leaf := range leafValidationErrors(ve) {
+			if _, ok := leaf.ErrorKind.(*kind.Required); ok && partial {
+				continue
+			}
+			diagnostics = append(diagnostics, schemaDiagnostics(file, leaf, locs)...)
+		}
+	}
+
+	diagnostics = append(diagnostics, misspelledKeys(file, doc.Content[0], docs[configSchemaURL], configSchemaURL, docs, "")...)
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		if diagnostics[i].Line != diagnostics[j].Line {
+			return diagnostics[i].Line < diagnostics[j].Line
+		}
+		return diagnostics[i].Column < diagnostics[j].Column
+	})
+
+	return diagnostics, nil
+}
+
+func compileConfigSchema() (*jsonschema.Schema, map[string]any, error) {
+	compileConfigSchemaOnce.Do(func() {
+		configSchemaDocs = map[string]any{}
+		c := jsonschema.NewCompiler()
+		c.UseRegexpEngine(ecmaRegexpCompile)
+
+		files := []string{"schemas/gen.config.schema.json"}
+		langFiles, err := fs.Glob(schemaFS, "schemas/languages/*.schema.json")
+		if err != nil {
+			configSchemaErr = err
+			return
+		}
+		files = append(files, langFiles...)
+
+		for _, file := range files {
+			data, err := schemaFS.ReadFile(file)
+			if err != nil {
+				configSchemaErr = err
+				return
+			}
+
+			doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+			if err != nil {
+				configSchemaErr = fmt.Errorf("could not parse %s: %w", file, err)
+				return
+			}
+
+			url := schemaBaseURL + strings.TrimPrefix(file, "schemas/")
+			if err := c.AddResource(url, doc); err != nil {
+				configSchemaErr = fmt.Errorf("could not load %s: %w", file, err)
+				return
+			}
+			configSchemaDocs[url] = doc
+		}
+
+		configSchema, configSchemaErr = c.Compile(configSchemaURL)
+	})
+
+	return configSchema, configSchemaDocs, configSchemaErr
+}
+
+var ecmaUnicodeEscape = regexp.MustCompile(`\\u([0-9a-fA-F]{4})`)
+
+// ecmaRegexpCompile compiles the ECMA 262 patterns used in the schemas, translating the \uXXXX
+// escapes that Go's regexp syntax doesn't support.
+func ecmaRegexpCompile(s string) (jsonschema.Regexp, error) {
+	return regexp.Compile(ecmaUnicodeEscape.ReplaceAllString(s, `\x{$1}`))
+}
+
+type nodeLocation struct {
+	key   *yaml.Node
+	value *yaml.Node
+}
+
+// nodeToInstance converts a yaml node into the JSON compatible representation the validator expects,
+// recording the location of every value by JSON pointer along the way.
+func nodeToInstance(n *yaml.Node, key *yaml.Node, pointer string, locs map[string]nodeLocation) any {
+	locs[pointer] = nodeLocation{key: key, value: n}
+
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return nil
+		}
+		return nodeToInstance(n.Content[0], key, pointer, locs)
+	case yaml.AliasNode:
+		return nodeToInstance(n.Alias, key, pointer, locs)
+	case yaml.MappingNode:
+		obj := map[string]any{}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+			if isMergeKey(k) {
+				merged, ok := nodeToInstance(v, k, pointer, map[string]nodeLocation{}).(map[string]any)
+				if ok {
+					for mk, mv := range merged {
+						if _, exists := obj[mk]; !exists {
+							obj[mk] = mv
+						}
+					}
+				}
+				continue
+			}
+			obj[k.Value] = nodeToInstance(v, k, pointer+"/"+escapePointerToken(k.Value), locs)
+		}
+		return obj
+	case yaml.SequenceNode:
+		arr := make([]any, 0, len(n.Content))
+		for i, c := range n.Content {
+			arr = append(arr, nodeToInstance(c, nil, pointer+"/"+strconv.Itoa(i), locs))
+		}
+		return arr
+	}
+
+	switch n.ShortTag() {
+	case "!!null":
+		return nil
+	case "!!bool":
+		var b bool
+		if err := n.Decode(&b); err == nil {
+			return b
+		}
+	case "!!int", "!!float":
+		var f float64
+		if err := n.Decode(&f); err == nil {
+			return json.Number(strconv.FormatFloat(f, 'f', -1, 64))
+		}
+	}
+
+	return n.Value
+}
+
+func leafValidationErrors(ve *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	if len(ve.Causes) == 0 {
+		return []*jsonschema.ValidationError{ve}
+	}
+
+	var leaves []*jsonschema.ValidationError
+	for _, cause := range ve.Causes {
+		leaves = append(leaves, leafValidationErrors(cause)...)
+	}
+	return leaves
+}
+
+var schemaMessagePrinter = message.NewPrinter(language.English)
+
+func schemaDiagnostics(file string, ve *jsonschema.ValidationError, locs map[string]nodeLocation) []SchemaDiagnostic {
+	pointer := ""
+	for _, tok := range ve.InstanceLocation {
+		pointer += "/" + escapePointerToken(tok)
+	}
+
+	// Report unknown properties against each offending key rather than the parent object
+	if ap, ok := ve.ErrorKind.(*kind.AdditionalProperties); ok {
+		diagnostics := make([]SchemaDiagnostic, 0, len(ap.Properties))
+		for _, prop := range ap.Properties {
+			propPointer := pointer + "/" + escapePointerToken(prop)
+			diagnostics = append(diagnostics, newSchemaDiagnostic(file, propPointer, locs[propPointer].key, fmt.Sprintf("property %q is not allowed", prop)))
+		}
+		return diagnostics
+	}
+
+	loc := locs[pointer]
+	return []SchemaDiagnostic{newSchemaDiagnostic(file, pointer, loc.value, ve.ErrorKind.LocalizedString(schemaMessagePrinter))}
+}
+
+func newSchemaDiagnostic(file, pointer string, n *yaml.Node, msg string) SchemaDiagnostic {
+	d := SchemaDiagnostic{
+		File:    file,
+		Pointer: pointer,
+		Message: msg,
+	}
+	if n != nil {
+		d.Line = n.Line
+		d.Column = n.Column
+	}
+	return d
+}
+
+// misspelledKeys walks the document alongside the raw schema and reports keys that aren't declared
+// but closely match a declared property, as these would otherwise be silently accepted wherever
+// the schema allows additional properties.
+func misspelledKeys(file string, n *yaml.Node, schemaDoc any, schemaURL string, docs map[string]any, pointer string) []SchemaDiagnostic {
+	schemaObj, schemaURL := resolveSchemaRef(schemaDoc, schemaURL, docs)
+	if schemaObj == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	props, _ := schemaObj["properties"].(map[string]any)
+	if len(props) == 0 {
+		return nil
+	}
+
+	var diagnostics []SchemaDiagnostic
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		keyPointer := pointer + "/" + escapePointerToken(k.Value)
+
+		if propSchema, ok := props[k.Value]; ok {
+			diagnostics = append(diagnostics, misspelledKeys(file, v, propSchema, schemaURL, docs, keyPointer)...)
+			continue
+		}
+
+		// Unknown keys are already reported by the validator if they aren't allowed
+		if ap, ok := schemaObj["additionalProperties"].(bool); ok && !ap {
+			continue
+		}
+
+		if suggestion := closestProperty(k.Value, props); suggestion != "" {
+			diagnostics = append(diagnostics, newSchemaDiagnostic(file, keyPointer, k, fmt.Sprintf("unknown property %q, did you mean %q?", k.Value, suggestion)))
+		}
+	}
+
+	return diagnostics
+}
+
+func resolveSchemaRef(schemaDoc any, schemaURL string, docs map[string]any) (map[string]any, string) {
+	for range 10 {
+		obj, ok := schemaDoc.(map[string]any)
+		if !ok {
+			return nil, schemaURL
+		}
+
+		ref, ok := obj["$ref"].(string)
+		if !ok {
+			return obj, schemaURL
+		}
+
+		file, fragment, _ := strings.Cut(ref, "#")
+		if file != "" {
+			schemaURL = schemaBaseURL + path.Clean(path.Join(path.Dir(strings.TrimPrefix(schemaURL, schemaBaseURL)), file))
+		}
+
+		schemaDoc = docs[schemaURL]
+		for _, tok := range strings.Split(strings.TrimPrefix(fragment, "/"), "/") {
+			if tok == "" {
+				continue
+			}
+			m, ok := schemaDoc.(map[string]any)
+			if !ok {
+				return nil, schemaURL
+			}
+			schemaDoc = m[unescapePointerToken(tok)]
+		}
+	}
+
+	return nil, schemaURL
+}
+
+func closestProperty(key string, props map[string]any) string {
+	best := ""
+	bestDistance := 3
+
+	for prop := range props {
+		if strings.EqualFold(prop, key) {
+			return prop
+		}
+
+		// Only suggest near misses for reasonably long keys to avoid noise
+		if len(key) < 8 {
+			continue
+		}
+
+		if d := editDistance(strings.ToLower(key), strings.ToLower(prop)); d < bestDistance || (d == bestDistance && prop < best) {
+			best = prop
+			bestDistance = d
+		}
+	}
+
+	if bestDistance > 2 {
+		return ""
+	}
+
+	return best
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
+func escapePointerToken(tok string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(tok, "~", "~0"), "/", "~1")
+}
+
+func unescapePointerToken(tok string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(tok, "~1", "/"), "~0", "~")
+}
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/speakeasy-api/sdk-gen-config/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfigSchema_Success(t *testing.T) {
+	err := ValidateConfigSchema("gen.yaml", []byte(testutils.ReadTestFile(t, "v200-gen.yaml")))
+	assert.NoError(t, err)
+}
+
+func TestValidateConfigSchema_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		genYaml string
+		want    []SchemaDiagnostic
+	}{
+		{
+			name: "reports misspelled keys",
+			genYaml: `configVersion: 2.0.0
+generation:
+  maintainOpenApiOrder: true
+  fixes:
+    nameResolutionFeb2O25: true
+`,
+			want: []SchemaDiagnostic{
+				{
+					File:    "gen.yaml",
+					Line:    3,
+					Column:  3,
+					Pointer: "/generation/maintainOpenApiOrder",
+					Message: `unknown property "maintainOpenApiOrder", did you mean "maintainOpenAPIOrder"?`,
+				},
+				{
+					File:    "gen.yaml",
+					Line:    5,
+					Column:  5,
+					Pointer: "/generation/fixes/nameResolutionFeb2O25",
+					Message: `unknown property "nameResolutionFeb2O25", did you mean "nameResolutionFeb2025"?`,
+				},
+			},
+		},
+		{
+			name: "reports invalid values and unknown properties",
+			genYaml: `configVersion: 2.0.0
+generation:
+  sdkClassName: speakeasy
+  auth:
+    hoistGlobalSecurity: "yes"
+    unknown: true
+  usageSnippets:
+    sdkInitStyle: factory
+go:
+  version: 1.0.0
+  maxMethodParams: lots
+`,
+			want: []SchemaDiagnostic{
+				{
+					File:    "gen.yaml",
+					Line:    5,
+					Column:  26,
+					Pointer: "/generation/auth/hoistGlobalSecurity",
+					Message: "got string, want boolean",
+				},
+				{
+					File:    "gen.yaml",
+					Line:    6,
+					Column:  5,
+					Pointer: "/generation/auth/unknown",
+					Message: `property "unknown" is not allowed`,
+				},
+				{
+					File:    "gen.yaml",
+					Line:    8,
+					Column:  19,
+					Pointer: "/generation/usageSnippets/sdkInitStyle",
+					Message: "value must be one of 'constructor', 'builder'",
+				},
+				{
+					File:    "gen.yaml",
+					Line:    11,
+					Column:  20,
+					Pointer: "/go/maxMethodParams",
+					Message: "got string, want number",
+				},
+			},
+		},
+		{
+			name: "reports missing required properties",
+			genYaml: `generation:
+  sdkClassName: speakeasy
+`,
+			want: []SchemaDiagnostic{
+				{
+					File:    "gen.yaml",
+					Line:    1,
+					Column:  1,
+					Pointer: "",
+					Message: "missing property 'configVersion'",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateConfigSchema("gen.yaml", []byte(tt.genYaml))
+			require.Error(t, err)
+
+			var schemaErr *SchemaValidationError
+			require.ErrorAs(t, err, &schemaErr)
+			assert.Equal(t, tt.want, schemaErr.Diagnostics)
+		})
+	}
+}
+
+func TestLoad_WithSchemaValidation(t *testing.T) {
+	dir := t.TempDir()
+	testutils.CreateTempFile(t, filepath.Join(dir, ".speakeasy"), "gen.yaml", `configVersion: 2.0.0
+generation:
+  maintainOpenApiOrder: true
+go:
+  version: 1.0.0
+`)
+
+	_, err := Load(dir, WithLanguages("go"))
+	require.NoError(t, err)
+
+	_, err = Load(dir, WithLanguages("go"), WithSchemaValidation())
+	var schemaErr *SchemaValidationError
+	require.ErrorAs(t, err, &schemaErr)
+	require.Len(t, schemaErr.Diagnostics, 1)
+	assert.Equal(t, filepath.Join(dir, ".speakeasy", "gen.yaml"), schemaErr.Diagnostics[0].File)
+	assert.Equal(t, 3, schemaErr.Diagnostics[0].Line)
+	assert.Equal(t, "/generation/maintainOpenApiOrder", schemaErr.Diagnostics[0].Pointer)
+}
+
+func TestLoad_WithSchemaValidation_Layers(t *testing.T) {
+	dir := t.TempDir()
+	speakeasyDir := filepath.Join(dir, ".speakeasy")
+	testutils.CreateTempFile(t, speakeasyDir, "base.yaml", `generation:
+  maintainOpenApiOrder: true
+`)
+	testutils.CreateTempFile(t, speakeasyDir, "gen.local.yaml", `generation:
+  deduplicateErrors: sometimes
+`)
+	testutils.CreateTempFile(t, speakeasyDir, "gen.yaml", `configVersion: 2.0.0
+extends:
+  - base.yaml
+generation:
+  sdkClassName: speakeasy
+go:
+  version: 1.0.0
+`)
+
+	// Missing required keys aren't reported for the base and local configs, which only hold some of the keys
+	_, err := Load(dir, WithLanguages("go"), WithSchemaValidation())
+	var schemaErr *SchemaValidationError
+	require.ErrorAs(t, err, &schemaErr)
+	require.Len(t, schemaErr.Diagnostics, 2)
+	assert.Equal(t, filepath.Join(speakeasyDir, "base.yaml"), schemaErr.Diagnostics[0].File)
+	assert.Equal(t, "/generation/maintainOpenApiOrder", schemaErr.Diagnostics[0].Pointer)
+	assert.Equal(t, filepath.Join(speakeasyDir, "gen.local.yaml"), schemaErr.Diagnostics[1].File)
+	assert.Equal(t, "/generation/deduplicateErrors", schemaErr.Diagnostics[1].Pointer)
+
+	// Configs at an older version aren't described by the schema
+	testutils.CreateTempFile(t, speakeasyDir, "gen.yaml", `configVersion: 1.0.0
+generation:
+  sdkClassName: speakeasy
+`)
+	_, err = Load(dir, WithLanguages("go"), WithSchemaValidation())
+	assert.False(t, errors.As(err, &schemaErr), "unexpected schema validation error: %v", err)
+}
@@ -0,0 +1,237 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidVersion = errors.New("invalid semantic version")
+
+// SemVer is a semantic version as described by https://semver.org, optionally prefixed with a "v".
+type SemVer struct {
+	Major      uint64
+	Minor      uint64
+	Patch      uint64
+	Prerelease []string
+	Build      []string
+
+	prefix string
+}
+
+// ParseSemVer parses a version such as "1.2.3", "v1.2.3" or "1.2.3-beta.1+build.5".
+func ParseSemVer(version string) (SemVer, error) {
+	v := SemVer{}
+
+	s := version
+	if strings.HasPrefix(s, "v") {
+		v.prefix = "v"
+		s = s[1:]
+	}
+
+	if before, build, ok := strings.Cut(s, "+"); ok {
+		ids, err := parseIdentifiers(build, false)
+		if err != nil {
+			return SemVer{}, fmt.Errorf("%w: %q build metadata %w", ErrInvalidVersion, version, err)
+		}
+		v.Build = ids
+		s = before
+	}
+
+	if before, prerelease, ok := strings.Cut(s, "-"); ok {
+		ids, err := parseIdentifiers(prerelease, true)
+		if err != nil {
+			return SemVer{}, fmt.Errorf("%w: %q prerelease %w", ErrInvalidVersion, version, err)
+		}
+		v.Prerelease = ids
+		s = before
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return SemVer{}, fmt.Errorf("%w: %q must have a major, minor and patch version", ErrInvalidVersion, version)
+	}
+
+	nums := make([]uint64, 0, 3)
+	for _, part := range parts {
+		if !isNumeric(part) || (len(part) > 1 && part[0] == '0') {
+			return SemVer{}, fmt.Errorf("%w: %q has an invalid version number %q", ErrInvalidVersion, version, part)
+		}
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return SemVer{}, fmt.Errorf("%w: %q has an invalid version number %q", ErrInvalidVersion, version, part)
+		}
+		nums = append(nums, n)
+	}
+	v.Major, v.Minor, v.Patch = nums[0], nums[1], nums[2]
+
+	return v, nil
+}
+
+func parseIdentifiers(s string, prerelease bool) ([]string, error) {
+	ids := strings.Split(s, ".")
+	for _, id := range ids {
+		if id == "" {
+			return nil, errors.New("has an empty identifier")
+		}
+		for _, r := range id {
+			if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-') {
+				return nil, fmt.Errorf("has an invalid identifier %q", id)
+			}
+		}
+		if prerelease && isNumeric(id) && len(id) > 1 && id[0] == '0' {
+			return nil, fmt.Errorf("has a numeric identifier with a leading zero %q", id)
+		}
+	}
+	return ids, nil
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (v SemVer) String() string {
+	s := fmt.Sprintf("%s%d.%d.%d", v.prefix, v.Major, v.Minor, v.Patch)
+	if len(v.Prerelease) > 0 {
+		s += "-" + strings.Join(v.Prerelease, ".")
+	}
+	if len(v.Build) > 0 {
+		s += "+" + strings.Join(v.Build, ".")
+	}
+	return s
+}
+
+// Compare returns -1, 0 or 1 depending on whether v has a lower, equal or higher precedence than other.
+// Build metadata doesn't affect precedence.
+func (v SemVer) Compare(other SemVer) int {
+	for _, c := range [][2]uint64{{v.Major, other.Major}, {v.Minor, other.Minor}, {v.Patch, other.Patch}} {
+		if c[0] != c[1] {
+			if c[0] < c[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	// A release has a higher precedence than any of its prereleases
+	switch {
+	case len(v.Prerelease) == 0 && len(other.Prerelease) == 0:
+		return 0
+	case len(v.Prerelease) == 0:
+		return 1
+	case len(other.Prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.Prerelease) && i < len(other.Prerelease); i++ {
+		if c := compareIdentifier(v.Prerelease[i], other.Prerelease[i]); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case len(v.Prerelease) < len(other.Prerelease):
+		return -1
+	case len(v.Prerelease) > len(other.Prerelease):
+		return 1
+	}
+	return 0
+}
+
+func compareIdentifier(a, b string) int {
+	aNum, bNum := isNumeric(a), isNumeric(b)
+	switch {
+	case aNum && bNum:
+		an, _ := strconv.ParseUint(a, 10, 64)
+		bn, _ := strconv.ParseUint(b, 10, 64)
+		switch {
+		case an < bn:
+			return -1
+		case an > bn:
+			return 1
+		}
+		return 0
+	case aNum:
+		return -1
+	case bNum:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+type BumpLevel string
+
+const (
+	BumpMajor      BumpLevel = "major"
+	BumpMinor      BumpLevel = "minor"
+	BumpPatch      BumpLevel = "patch"
+	BumpPrerelease BumpLevel = "prerelease"
+)
+
+// Bump returns the next version at the given level. Bumping a prerelease to the release it precedes
+// doesn't increment further, so 1.3.0-beta.2 bumped by minor is 1.3.0. If preid is set the result is a
+// prerelease with that identifier, for example 1.2.3 bumped by minor with a preid of "beta" is 1.3.0-beta.0.
+// Build metadata is dropped.
+func (v SemVer) Bump(level BumpLevel, preid string) (SemVer, error) {
+	next := SemVer{Major: v.Major, Minor: v.Minor, Patch: v.Patch, prefix: v.prefix}
+	isPrerelease := len(v.Prerelease) > 0
+
+	switch level {
+	case BumpMajor:
+		if !isPrerelease || v.Minor != 0 || v.Patch != 0 {
+			next.Major, next.Minor, next.Patch = v.Major+1, 0, 0
+		}
+	case BumpMinor:
+		if !isPrerelease || v.Patch != 0 {
+			next.Minor, next.Patch = v.Minor+1, 0
+		}
+	case BumpPatch:
+		if !isPrerelease {
+			next.Patch = v.Patch + 1
+		}
+	case BumpPrerelease:
+		if !isPrerelease {
+			next.Patch = v.Patch + 1
+			break
+		}
+		next.Prerelease = nextPrerelease(v.Prerelease, preid)
+		return next, nil
+	default:
+		return SemVer{}, fmt.Errorf("unknown bump level %q, expected one of major, minor, patch or prerelease", level)
+	}
+
+	if preid != "" || level == BumpPrerelease {
+		next.Prerelease = []string{"0"}
+		if preid != "" {
+			next.Prerelease = []string{preid, "0"}
+		}
+	}
+
+	return next, nil
+}
+
+// nextPrerelease increments the last numeric identifier of prerelease, or starts a new series if preid differs.
+func nextPrerelease(prerelease []string, preid string) []string {
+	if preid != "" && prerelease[0] != preid {
+		return []string{preid, "0"}
+	}
+
+	next := append([]string{}, prerelease...)
+	last := next[len(next)-1]
+	if !isNumeric(last) {
+		return append(next, "0")
+	}
+
+	n, _ := strconv.ParseUint(last, 10, 64)
+	next[len(next)-1] = strconv.FormatUint(n+1, 10)
+	return next
+}
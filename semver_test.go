@@ -0,0 +1,119 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSemVer(t *testing.T) {
+	tests := []struct {
+		version string
+		want    SemVer
+		wantErr bool
+	}{
+		{version: "1.2.3", want: SemVer{Major: 1, Minor: 2, Patch: 3}},
+		{version: "v0.10.0", want: SemVer{Minor: 10, prefix: "v"}},
+		{version: "1.2.3-beta.1+build.5", want: SemVer{Major: 1, Minor: 2, Patch: 3, Prerelease: []string{"beta", "1"}, Build: []string{"build", "5"}}},
+		{version: "1.2.3+20240101", want: SemVer{Major: 1, Minor: 2, Patch: 3, Build: []string{"20240101"}}},
+		{version: "1.2", wantErr: true},
+		{version: "01.2.3", wantErr: true},
+		{version: "1.2.3-beta..1", wantErr: true},
+		{version: "1.2.3-01", wantErr: true},
+		{version: "1.2.3-beta_1", wantErr: true},
+		{version: "latest", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got, err := ParseSemVer(tt.version)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidVersion)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.version, got.String())
+		})
+	}
+}
+
+func TestSemVer_Compare(t *testing.T) {
+	// In order of increasing precedence, as listed by the semver spec
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.1.0",
+		"2.0.0",
+	}
+
+	for i := range ordered {
+		for j := range ordered {
+			a, err := ParseSemVer(ordered[i])
+			require.NoError(t, err)
+			b, err := ParseSemVer(ordered[j])
+			require.NoError(t, err)
+
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = 1
+			}
+			assert.Equal(t, want, a.Compare(b), "%s compared to %s", ordered[i], ordered[j])
+		}
+	}
+
+	a, _ := ParseSemVer("1.0.0+build.1")
+	b, _ := ParseSemVer("1.0.0+build.2")
+	assert.Equal(t, 0, a.Compare(b))
+}
+
+func TestSemVer_Bump(t *testing.T) {
+	tests := []struct {
+		version string
+		level   BumpLevel
+		preid   string
+		want    string
+		wantErr string
+	}{
+		{version: "1.2.3", level: BumpMajor, want: "2.0.0"},
+		{version: "1.2.3", level: BumpMinor, want: "1.3.0"},
+		{version: "1.2.3", level: BumpPatch, want: "1.2.4"},
+		{version: "v1.2.3+build.1", level: BumpPatch, want: "v1.2.4"},
+		{version: "1.2.3", level: BumpPrerelease, want: "1.2.4-0"},
+		{version: "1.2.3", level: BumpPrerelease, preid: "beta", want: "1.2.4-beta.0"},
+		{version: "1.2.3", level: BumpMinor, preid: "beta", want: "1.3.0-beta.0"},
+		{version: "1.2.4-beta.0", level: BumpPrerelease, preid: "beta", want: "1.2.4-beta.1"},
+		{version: "1.2.4-beta.9", level: BumpPrerelease, want: "1.2.4-beta.10"},
+		{version: "1.2.4-beta", level: BumpPrerelease, want: "1.2.4-beta.0"},
+		{version: "1.2.4-beta.3", level: BumpPrerelease, preid: "rc", want: "1.2.4-rc.0"},
+		{version: "1.2.4-beta.3", level: BumpPatch, want: "1.2.4"},
+		{version: "1.3.0-beta.3", level: BumpMinor, want: "1.3.0"},
+		{version: "1.3.1-beta.3", level: BumpMinor, want: "1.4.0"},
+		{version: "2.0.0-rc.1", level: BumpMajor, want: "2.0.0"},
+		{version: "2.1.0-rc.1", level: BumpMajor, want: "3.0.0"},
+		{version: "1.2.3", level: "huge", wantErr: `unknown bump level "huge", expected one of major, minor, patch or prerelease`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version+" "+string(tt.level)+" "+tt.preid, func(t *testing.T) {
+			v, err := ParseSemVer(tt.version)
+			require.NoError(t, err)
+
+			got, err := v.Bump(tt.level, tt.preid)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
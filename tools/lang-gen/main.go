@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// typeNames maps a language to the name of its generated config struct
+var typeNames = map[string]string{
+	"go":         "GoConfig",
+	"typescript": "TypeScriptConfig",
+	"python":     "PythonConfig",
+	"java":       "JavaConfig",
+	"csharp":     "CSharpConfig",
+	"unity":      "UnityConfig",
+	"php":        "PHPConfig",
+	"ruby":       "RubyConfig",
+	"postman":    "PostmanConfig",
+	"terraform":  "TerraformConfig",
+}
+
+type property struct {
+	Description string `json:"description"`
+	Type        any    `json:"type"`
+}
+
+type languageSchema struct {
+	Properties map[string]property `json:"properties"`
+}
+
+func main() {
+	var (
+		schemasDir string
+		out        string
+	)
+	flag.StringVar(&schemasDir, "schemas", "../../schemas/languages", "directory containing the language schemas")
+	flag.StringVar(&out, "out", "-", "output file path or - for stdout")
+	flag.Parse()
+
+	files, err := filepath.Glob(filepath.Join(schemasDir, "*.schema.json"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "glob: %v\n", err)
+		os.Exit(1)
+	}
+	sort.Strings(files)
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by tools/lang-gen from schemas/languages. DO NOT EDIT.\n\n")
+	b.WriteString("package config\n\n")
+
+	var langs []string
+
+	for _, file := range files {
+		lang := strings.TrimSuffix(filepath.Base(file), ".schema.json")
+		langs = append(langs, lang)
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "read %s: %v\n", file, err)
+			os.Exit(1)
+		}
+
+		var schema languageSchema
+		if err := json.Unmarshal(data, &schema); err != nil {
+			fmt.Fprintf(os.Stderr, "unmarshal %s: %v\n", file, err)
+			os.Exit(1)
+		}
+
+		writeStruct(&b, lang, schema)
+	}
+
+	b.WriteString("// NewTypedLanguageConfig returns a pointer to a zero value of the typed config for the given language,\n")
+	b.WriteString("// or nil if the language has no schema.\n")
+	b.WriteString("func NewTypedLanguageConfig(lang string) any {\n\tswitch lang {\n")
+	for _, lang := range langs {
+		fmt.Fprintf(&b, "\tcase %q:\n\t\treturn &%s{}\n", lang, typeName(lang))
+	}
+	b.WriteString("\t}\n\n\treturn nil\n}\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "format: %v\n", err)
+		os.Exit(1)
+	}
+
+	if out == "-" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := os.WriteFile(out, src, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "write %s: %v\n", out, err)
+		os.Exit(1)
+	}
+}
+
+func writeStruct(b *bytes.Buffer, lang string, schema languageSchema) {
+	name := typeName(lang)
+
+	props := make([]string, 0, len(schema.Properties))
+	for prop := range schema.Properties {
+		props = append(props, prop)
+	}
+	sort.Strings(props)
+
+	fmt.Fprintf(b, "// %s is the typed configuration for %s SDKs, generated from schemas/languages/%s.schema.json.\n", name, lang, lang)
+	fmt.Fprintf(b, "type %s struct {\n", name)
+
+	for _, prop := range props {
+		p := schema.Properties[prop]
+
+		if p.Description != "" {
+			fmt.Fprintf(b, "\t// %s\n", strings.TrimSpace(p.Description))
+		}
+
+		// Version is always present on a LanguageConfig so it isn't optional
+		if prop == "version" {
+			fmt.Fprintf(b, "\tVersion string `yaml:\"version\"`\n")
+			continue
+		}
+
+		fmt.Fprintf(b, "\t%s %s `yaml:\"%s,omitempty\"`\n", fieldName(prop), goType(p.Type), prop)
+	}
+
+	b.WriteString("\t// Captures any keys that are not described by the schema\n")
+	b.WriteString("\tAdditionalProperties map[string]any `yaml:\",inline\"`\n")
+	b.WriteString("}\n\n")
+}
+
+func typeName(lang string) string {
+	if name, ok := typeNames[lang]; ok {
+		return name
+	}
+	return fieldName(lang) + "Config"
+}
+
+// initialisms are written in upper case wherever they appear as a word in a field name, so that documentationUrl and
+// ossrhURL become DocumentationURL and OssrhURL
+var initialisms = map[string]bool{
+	"API":  true,
+	"HTTP": true,
+	"ID":   true,
+	"JSON": true,
+	"SDK":  true,
+	"URI":  true,
+	"URL":  true,
+}
+
+func fieldName(prop string) string {
+	var b strings.Builder
+	for _, word := range camelWords(prop) {
+		if upper := strings.ToUpper(word); initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		r := []rune(word)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	return b.String()
+}
+
+// camelWords splits a camelCase name into its words, keeping runs of capitals together, so that ossrhURL splits into
+// ossrh and URL and URLPath into URL and Path
+func camelWords(s string) []string {
+	r := []rune(s)
+
+	var words []string
+	start := 0
+	for i := 1; i < len(r); i++ {
+		if !unicode.IsUpper(r[i]) {
+			continue
+		}
+		if !unicode.IsUpper(r[i-1]) || (i+1 < len(r) && unicode.IsLower(r[i+1])) {
+			words = append(words, string(r[start:i]))
+			start = i
+		}
+	}
+	return append(words, string(r[start:]))
+}
+
+// goType returns the type of a field, scalars are pointers so that unset and zero values can be told apart
+func goType(t any) string {
+	switch t {
+	case "string":
+		return "*string"
+	case "boolean":
+		return "*bool"
+	case "number":
+		return "*float64"
+	case "integer":
+		return "*int64"
+	case "array":
+		return "[]any"
+	case "object":
+		return "map[string]any"
+	default:
+		return "any"
+	}
+}
@@ -0,0 +1,375 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type UpgradeChangeKind string
+
+const (
+	// UpgradeChangeMoved is a key that was moved to a different parent or file by a migration
+	UpgradeChangeMoved UpgradeChangeKind = "moved"
+	// UpgradeChangeRenamed is a key that was renamed in place by a migration
+	UpgradeChangeRenamed UpgradeChangeKind = "renamed"
+	// UpgradeChangeDeleted is a key that was removed
+	UpgradeChangeDeleted UpgradeChangeKind = "deleted"
+	// UpgradeChangeAdded is a key that was introduced by a migration
+	UpgradeChangeAdded UpgradeChangeKind = "added"
+	// UpgradeChangeDefaulted is a key that was populated with a default value
+	UpgradeChangeDefaulted UpgradeChangeKind = "defaulted"
+	// UpgradeChangeModified is a key whose value was changed in place
+	UpgradeChangeModified UpgradeChangeKind = "modified"
+)
+
+// UpgradeChange describes a single key affected by an upgrade. Paths are dotted key paths within the file.
+type UpgradeChange struct {
+	Kind    UpgradeChangeKind `json:"kind"`
+	File    string            `json:"file"`
+	Path    string            `json:"path"`
+	NewFile string            `json:"newFile,omitempty"` // Only set for moved and renamed keys
+	NewPath string            `json:"newPath,omitempty"` // Only set for moved and renamed keys
+	Before  any               `json:"before,omitempty"`
+	After   any               `json:"after,omitempty"`
+}
+
+func (c UpgradeChange) String() string {
+	file := filepath.Base(c.File)
+
+	switch c.Kind {
+	case UpgradeChangeMoved, UpgradeChangeRenamed:
+		return fmt.Sprintf("%s %s:%s -> %s:%s (%s)", c.Kind, file, c.Path, filepath.Base(c.NewFile), c.NewPath, formatChangeValue(c.After))
+	case UpgradeChangeDeleted:
+		return fmt.Sprintf("%s %s:%s (was %s)", c.Kind, file, c.Path, formatChangeValue(c.Before))
+	case UpgradeChangeModified:
+		return fmt.Sprintf("%s %s:%s %s -> %s", c.Kind, file, c.Path, formatChangeValue(c.Before), formatChangeValue(c.After))
+	default:
+		return fmt.Sprintf("%s %s:%s = %s", c.Kind, file, c.Path, formatChangeValue(c.After))
+	}
+}
+
+// PlannedFile holds the contents of a file before and after the upgrade.
+type PlannedFile struct {
+	Path   string
+	Before []byte // nil if the file doesn't exist yet
+	After  []byte
+}
+
+// UpgradePlan describes everything Load would change in gen.yaml and gen.lock, without writing anything.
+type UpgradePlan struct {
+	FromVersion string
+	ToVersion   string
+	Changes     []UpgradeChange
+	Files       []PlannedFile
+	Config      *Config // The config as it would be loaded after the upgrade
+}
+
+// HasChanges returns true if the upgrade would modify any file.
+func (p *UpgradePlan) HasChanges() bool {
+	return len(p.Changes) > 0
+}
+
+func (p *UpgradePlan) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "upgrade configVersion %s -> %s\n", displayVersion(p.FromVersion), displayVersion(p.ToVersion))
+	for _, c := range p.Changes {
+		sb.WriteString("  " + c.String() + "\n")
+	}
+	return sb.String()
+}
+
+// PlanUpgrade performs a dry run of Load with the provided options, returning every key that would be moved,
+// renamed, deleted, added or defaulted in gen.yaml and gen.lock along with its before and after values.
+// Nothing is written to disk. WithUpgradeFunc must be provided, as it is for upgrades performed by Load.
+func PlanUpgrade(dir string, opts ...Option) (*UpgradePlan, error) {
+	o := applyOptions(opts)
+	if o.UpgradeFunc == nil {
+		return nil, errors.New("an UpgradeFunc is required to plan an upgrade")
+	}
+
+	recorder := &writeRecorder{
+		original: map[string][]byte{},
+		migrated: map[string][]byte{},
+		final:    map[string][]byte{},
+	}
+
+	opts = append(opts, WithDontWrite(), func(o *options) {
+		o.recorder = recorder
+	})
+
+	cfg, err := Load(dir, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &UpgradePlan{
+		ToVersion: cfg.Config.ConfigVersion,
+		Config:    cfg,
+	}
+
+	originals := map[string]map[string]any{}
+	migrated := map[string]map[string]any{}
+	finals := map[string]map[string]any{}
+
+	for _, path := range recorder.paths {
+		for _, doc := range []struct {
+			data []byte
+			out  map[string]map[string]any
+			ok   bool
+		}{
+			{recorder.original[path], originals, true},
+			{recorder.migrated[path], migrated, recorder.migrated[path] != nil},
+			{recorder.final[path], finals, true},
+		} {
+			if !doc.ok {
+				continue
+			}
+
+			flat := map[string]any{}
+			if len(doc.data) > 0 {
+				var m map[string]any
+				if err := yaml.Unmarshal(doc.data, &m); err != nil {
+					return nil, fmt.Errorf("could not unmarshal %s: %w", path, err)
+				}
+				flattenMap("", m, flat)
+			}
+			doc.out[path] = flat
+		}
+
+		if !bytes.Equal(recorder.original[path], recorder.final[path]) {
+			plan.Files = append(plan.Files, PlannedFile{
+				Path:   path,
+				Before: recorder.original[path],
+				After:  recorder.final[path],
+			})
+		}
+	}
+
+	if v, ok := originals[cfg.ConfigPath]["configVersion"].(string); ok {
+		plan.FromVersion = v
+	}
+
+	// First the changes made by migrations, then anything populated afterwards by defaults or transformers
+	defaultsBase := map[string]map[string]any{}
+	if len(migrated) > 0 {
+		plan.Changes = append(plan.Changes, diffDocuments(recorder.paths, originals, migrated, UpgradeChangeAdded)...)
+		for _, path := range recorder.paths {
+			if m, ok := migrated[path]; ok {
+				defaultsBase[path] = m
+			} else {
+				defaultsBase[path] = originals[path]
+			}
+		}
+	} else {
+		defaultsBase = originals
+	}
+	plan.Changes = append(plan.Changes, diffDocuments(recorder.paths, defaultsBase, finals, UpgradeChangeDefaulted)...)
+
+	return plan, nil
+}
+
+// writeRecorder captures the writes Load makes so that they can be reported instead of written.
+type writeRecorder struct {
+	paths    []string
+	original map[string][]byte
+	migrated map[string][]byte
+	final    map[string][]byte
+}
+
+func (r *writeRecorder) record(path string, original, data []byte) {
+	if _, ok := r.final[path]; !ok {
+		r.paths = append(r.paths, path)
+		r.original[path] = original
+	}
+	r.final[path] = data
+}
+
+// markMigrated snapshots the files written so far as the output of the migrations.
+func (r *writeRecorder) markMigrated() {
+	for path, data := range r.final {
+		r.migrated[path] = data
+	}
+}
+
+// diffDocuments compares flattened documents across files, pairing removed and added keys into moves and renames.
+func diffDocuments(paths []string, before, after map[string]map[string]any, addedKind UpgradeChangeKind) []UpgradeChange {
+	type key struct {
+		file string
+		path string
+	}
+
+	var removed, added []key
+	var changes []UpgradeChange
+
+	for _, file := range paths {
+		b, a := before[file], after[file]
+
+		for _, p := range sortedKeys(b) {
+			av, ok := a[p]
+			if !ok {
+				removed = append(removed, key{file, p})
+				continue
+			}
+			if !reflect.DeepEqual(b[p], av) {
+				changes = append(changes, UpgradeChange{
+					Kind:   UpgradeChangeModified,
+					File:   file,
+					Path:   p,
+					Before: b[p],
+					After:  av,
+				})
+			}
+		}
+		for _, p := range sortedKeys(a) {
+			if _, ok := b[p]; !ok {
+				added = append(added, key{file, p})
+			}
+		}
+	}
+
+	matched := map[key]bool{}
+
+	for _, r := range removed {
+		value := before[r.file][r.path]
+
+		var match *key
+		for i, a := range added {
+			if matched[a] || !reflect.DeepEqual(after[a.file][a.path], value) {
+				continue
+			}
+
+			// Keys keeping their name are matched on value alone, otherwise only distinctive values are considered
+			// so that unrelated flags that happen to share a value aren't paired up
+			if strings.EqualFold(leafKey(a.path), leafKey(r.path)) {
+				match = &added[i]
+				break
+			}
+			if match == nil && isDistinctiveValue(value) {
+				match = &added[i]
+			}
+		}
+
+		if match == nil {
+			changes = append(changes, UpgradeChange{
+				Kind:   UpgradeChangeDeleted,
+				File:   r.file,
+				Path:   r.path,
+				Before: value,
+			})
+			continue
+		}
+
+		matched[*match] = true
+
+		kind := UpgradeChangeMoved
+		if match.file == r.file && parentKey(match.path) == parentKey(r.path) {
+			kind = UpgradeChangeRenamed
+		}
+
+		changes = append(changes, UpgradeChange{
+			Kind:    kind,
+			File:    r.file,
+			Path:    r.path,
+			NewFile: match.file,
+			NewPath: match.path,
+			Before:  value,
+			After:   after[match.file][match.path],
+		})
+	}
+
+	for _, a := range added {
+		if matched[a] {
+			continue
+		}
+		changes = append(changes, UpgradeChange{
+			Kind:  addedKind,
+			File:  a.file,
+			Path:  a.path,
+			After: after[a.file][a.path],
+		})
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].File != changes[j].File {
+			return fileIndex(paths, changes[i].File) < fileIndex(paths, changes[j].File)
+		}
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes
+}
+
+// flattenMap flattens nested maps into dotted paths. Sequences and empty maps are treated as leaf values.
+func flattenMap(prefix string, m map[string]any, out map[string]any) {
+	for k, v := range m {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+
+		if nested, ok := v.(map[string]any); ok && len(nested) > 0 {
+			flattenMap(path, nested, out)
+			continue
+		}
+
+		out[path] = v
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func leafKey(path string) string {
+	return path[strings.LastIndex(path, ".")+1:]
+}
+
+func parentKey(path string) string {
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		return path[:i]
+	}
+	return ""
+}
+
+func isDistinctiveValue(v any) bool {
+	switch val := v.(type) {
+	case string:
+		return len(val) >= 4
+	case map[string]any:
+		return len(val) > 0
+	case []any:
+		return len(val) > 0
+	default:
+		return false
+	}
+}
+
+func fileIndex(paths []string, file string) int {
+	for i, p := range paths {
+		if p == file {
+			return i
+		}
+	}
+	return len(paths)
+}
+
+func formatChangeValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/speakeasy-api/sdk-gen-config/lockfile"
+	"github.com/speakeasy-api/sdk-gen-config/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanUpgrade_Success(t *testing.T) {
+	getUUID = func() string {
+		return "123"
+	}
+	lockfile.GetUUID = getUUID
+
+	dir := t.TempDir()
+	speakeasyDir := filepath.Join(dir, ".speakeasy")
+	genYaml := testutils.ReadTestFile(t, "v100-gen.yaml")
+	testutils.CreateTempFile(t, speakeasyDir, "gen.yaml", genYaml)
+
+	configPath := filepath.Join(speakeasyDir, "gen.yaml")
+	lockFilePath := filepath.Join(speakeasyDir, "gen.lock")
+
+	plan, err := PlanUpgrade(dir, WithUpgradeFunc(testUpdateLang), WithLanguages("go"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "1.0.0", plan.FromVersion)
+	assert.Equal(t, Version, plan.ToVersion)
+	assert.True(t, plan.HasChanges())
+
+	for _, want := range []UpgradeChange{
+		{Kind: UpgradeChangeModified, File: configPath, Path: "configVersion", Before: "1.0.0", After: Version},
+		{Kind: UpgradeChangeDeleted, File: configPath, Path: "generation.comments.omitDescriptionIfSummaryPresent", Before: true},
+		{Kind: UpgradeChangeDeleted, File: configPath, Path: "generation.tagNamespacingDisabled", Before: false},
+		{Kind: UpgradeChangeMoved, File: configPath, Path: "management.docVersion", NewFile: lockFilePath, NewPath: "management.docVersion", Before: "0.3.0", After: "0.3.0"},
+		{Kind: UpgradeChangeMoved, File: configPath, Path: "features.go.core", NewFile: lockFilePath, NewPath: "features.go.core", Before: "2.90.0", After: "2.90.0"},
+		{Kind: UpgradeChangeAdded, File: lockFilePath, Path: "management.releaseVersion", After: "1.3.0"},
+		{Kind: UpgradeChangeDefaulted, File: configPath, Path: "generation.usageSnippets.sdkInitStyle", After: "constructor"},
+	} {
+		assert.Contains(t, plan.Changes, want)
+	}
+	assert.Contains(t, plan.String(), "moved gen.yaml:management.docChecksum -> gen.lock:management.docChecksum")
+
+	require.Len(t, plan.Files, 2)
+	assert.Equal(t, genYaml, string(plan.Files[0].Before))
+	assert.Nil(t, plan.Files[1].Before)
+	assert.Contains(t, string(plan.Files[1].After), "docVersion: 0.3.0")
+
+	// Nothing was written
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, genYaml, string(data))
+	assert.NoFileExists(t, lockFilePath)
+}
+
+func TestPlanUpgrade_Renamed(t *testing.T) {
+	dir := t.TempDir()
+	testutils.CreateTempFile(t, filepath.Join(dir, ".speakeasy"), "gen.yaml", `configVersion: 2.0.0
+generation:
+  sdkClassName: speakeasy
+go:
+  version: 1.0.0
+`)
+	testutils.CreateTempFile(t, filepath.Join(dir, ".speakeasy"), "gen.lock", testutils.ReadTestFile(t, "v200-gen.lock"))
+
+	registry := NewMigrationRegistry(builtinMigrations()...)
+	require.NoError(t, registry.Register(Migration{
+		From: "2.0.0",
+		To:   "3.0.0",
+		Migrate: func(cfg, lockFile map[string]any, _ UpgradeFunc) (map[string]any, map[string]any, error) {
+			generation := cfg["generation"].(map[string]any)
+			generation["rootClassName"] = generation["sdkClassName"]
+			delete(generation, "sdkClassName")
+			return cfg, lockFile, nil
+		},
+	}))
+
+	plan, err := PlanUpgrade(dir, WithUpgradeFunc(testUpdateLang), WithLanguages("go"), WithMigrationRegistry(registry), WithTargetVersion("3.0.0"))
+	require.NoError(t, err)
+
+	configPath := filepath.Join(dir, ".speakeasy", "gen.yaml")
+	assert.Contains(t, plan.Changes, UpgradeChange{
+		Kind:    UpgradeChangeRenamed,
+		File:    configPath,
+		Path:    "generation.sdkClassName",
+		NewFile: configPath,
+		NewPath: "generation.rootClassName",
+		Before:  "speakeasy",
+		After:   "speakeasy",
+	})
+}
+
+func TestPlanUpgrade_Error(t *testing.T) {
+	dir := t.TempDir()
+	testutils.CreateTempFile(t, filepath.Join(dir, ".speakeasy"), "gen.yaml", testutils.ReadTestFile(t, "v100-gen.yaml"))
+
+	_, err := PlanUpgrade(dir, WithLanguages("go"))
+	assert.EqualError(t, err, "an UpgradeFunc is required to plan an upgrade")
+}
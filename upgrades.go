@@ -11,32 +11,6 @@ var ErrFailedUpgrade = errors.New("failed to upgrade config")
 
 type UpgradeFunc func(target, template, oldVersion, newVersion string, cfg map[string]any) (map[string]any, error)
 
-func upgrade(currentVersion string, cfg map[string]any, lockFile map[string]any, uf UpgradeFunc) (map[string]any, map[string]any, error) {
-	if currentVersion == "" {
-		var err error
-		currentVersion, cfg, err = upgradeToV100(cfg, uf)
-		if err != nil {
-			return nil, nil, err
-		}
-	}
-
-	if currentVersion == v1 {
-		var err error
-		currentVersion, cfg, lockFile, err = upgradeToV200(cfg, uf)
-		if err != nil {
-			return nil, nil, err
-		}
-	}
-
-	// Put upgrade logic for future versions here, also upgrade incrementally between versions
-
-	if currentVersion != Version {
-		return nil, nil, ErrFailedUpgrade
-	}
-
-	return cfg, lockFile, nil
-}
-
 func upgradeToV100(cfg map[string]any, uf UpgradeFunc) (string, map[string]any, error) {
 	generation := map[string]any{}
 	upgraded := map[string]any{
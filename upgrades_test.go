@@ -171,7 +171,7 @@ func Test_upgrade_Success(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			upgraded, lockFile, err := upgrade(tt.args.currentVersion, tt.args.cfg, tt.args.lockFile, testUpdateLang)
+			upgraded, lockFile, err := DefaultMigrations.Migrate(tt.args.currentVersion, Version, tt.args.cfg, tt.args.lockFile, testUpdateLang)
 			assert.NoError(t, err)
 			assert.Equal(t, tt.wantCfg, upgraded)
 			assert.Equal(t, tt.wantLockFile, lockFile)
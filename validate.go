@@ -0,0 +1,124 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// FieldError describes a single configuration field that violates the rules declared by its SDKGenConfigField.
+type FieldError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) String() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// FieldValidationError is returned when one or more configuration fields are invalid.
+type FieldValidationError struct {
+	Errors []FieldError
+}
+
+func (e *FieldValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		msgs = append(msgs, fe.String())
+	}
+	return fmt.Sprintf("gen.yaml has invalid fields:\n%s", strings.Join(msgs, "\n"))
+}
+
+// ValidateFields checks the generation fields of cfg against the Required, RequiredForPublishing, ValidationRegex and
+// ValidationMessage rules declared by GetGenerationDefaults, along with any additional fields provided, such as those
+// returned for a language. Fields with a Language are checked under that language's key if it is configured.
+// All violations are returned as a *FieldValidationError.
+func ValidateFields(cfg Config, publishing bool, fields ...SDKGenConfigField) error {
+	var errs []FieldError
+
+	type rule struct {
+		path  string
+		field SDKGenConfigField
+	}
+
+	var rules []rule
+	for _, field := range GetGenerationDefaults(false) {
+		rules = append(rules, rule{path: "generation." + field.Name, field: field})
+	}
+	for _, field := range fields {
+		path := "generation." + field.Name
+		if field.Language != nil {
+			if _, ok := cfg.Config.Languages[*field.Language]; !ok {
+				continue
+			}
+			path = *field.Language + "." + field.Name
+		}
+		rules = append(rules, rule{path: path, field: field})
+	}
+
+	for _, r := range rules {
+		value, err := cfg.Config.Get(r.path)
+		if err != nil {
+			if errors.Is(err, ErrUnknownPath) {
+				// Declared fields without a corresponding key can't be set, so there is nothing to check
+				continue
+			}
+			return err
+		}
+
+		if msg := validateField(r.field, value, publishing); msg != "" {
+			errs = append(errs, FieldError{Path: r.path, Message: msg})
+		}
+	}
+
+	if len(errs) > 0 {
+		return &FieldValidationError{Errors: errs}
+	}
+
+	return nil
+}
+
+// FieldValidator returns a ValidateFunc that applies ValidateFields, for use WithValidateFunc.
+func FieldValidator(publishing bool, fields ...SDKGenConfigField) ValidateFunc {
+	return func(cfg Config) error {
+		return ValidateFields(cfg, publishing, fields...)
+	}
+}
+
+func validateField(field SDKGenConfigField, value any, publishing bool) string {
+	required := field.Required || (publishing && field.RequiredForPublishing != nil && *field.RequiredForPublishing)
+
+	if value == nil || reflect.ValueOf(value).IsZero() {
+		if !required {
+			return ""
+		}
+		if publishing && !field.Required {
+			return "is required for publishing"
+		}
+		return "is required"
+	}
+
+	if field.ValidationRegex == nil {
+		return ""
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		s = fmt.Sprint(value)
+	}
+
+	re, err := regexp.Compile(*field.ValidationRegex)
+	if err != nil {
+		return fmt.Sprintf("has an invalid validation regex: %s", err)
+	}
+	if re.MatchString(s) {
+		return ""
+	}
+
+	if field.ValidationMessage != nil {
+		return *field.ValidationMessage
+	}
+	return fmt.Sprintf("must match %s", *field.ValidationRegex)
+}
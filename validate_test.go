@@ -0,0 +1,116 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/speakeasy-api/openapi/pointer"
+	"github.com/speakeasy-api/sdk-gen-config/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerationDefaults_Tags(t *testing.T) {
+	for _, newSDK := range []bool{false, true} {
+		_, err := generationDefaults(newSDK)
+		assert.NoError(t, err)
+	}
+}
+
+func TestValidateFields(t *testing.T) {
+	goFields := []SDKGenConfigField{
+		{
+			Name:                  "packageName",
+			Language:              pointer.From("go"),
+			RequiredForPublishing: pointer.From(true),
+		},
+		{
+			Name:              "maxMethodParams",
+			Language:          pointer.From("go"),
+			ValidationRegex:   pointer.From(`^\d$`),
+			ValidationMessage: pointer.From("Must be a single digit"),
+		},
+		{
+			Name:     "version",
+			Language: pointer.From("typescript"),
+			Required: true,
+		},
+	}
+
+	tests := []struct {
+		name       string
+		mutate     func(cfg *Configuration)
+		publishing bool
+		want       []FieldError
+	}{
+		{
+			name: "default config is valid",
+		},
+		{
+			name: "reports every invalid generation field",
+			mutate: func(cfg *Configuration) {
+				cfg.Generation.SDKClassName = "My SDK"
+				cfg.Generation.BaseServerURL = "localhost"
+			},
+			want: []FieldError{
+				{Path: "generation.baseServerURL", Message: "Must be a valid server URL"},
+				{Path: "generation.sdkClassName", Message: "Letters, numbers, or .-_ only"},
+			},
+		},
+		{
+			name: "required for publishing is only enforced when publishing",
+			mutate: func(cfg *Configuration) {
+				cfg.Languages["go"] = LanguageConfig{Version: "1.0.0", Cfg: map[string]any{"maxMethodParams": 12}}
+			},
+			publishing: true,
+			want: []FieldError{
+				{Path: "go.packageName", Message: "is required for publishing"},
+				{Path: "go.maxMethodParams", Message: "Must be a single digit"},
+			},
+		},
+		{
+			name: "valid language fields",
+			mutate: func(cfg *Configuration) {
+				cfg.Languages["go"] = LanguageConfig{Version: "1.0.0", Cfg: map[string]any{"packageName": "openapi", "maxMethodParams": 4}}
+			},
+			publishing: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := GetDefaultConfig(false, nil, map[string]bool{"go": false})
+			require.NoError(t, err)
+
+			if tt.mutate != nil {
+				tt.mutate(cfg)
+			}
+
+			err = ValidateFields(Config{Config: cfg}, tt.publishing, goFields...)
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			var fieldErr *FieldValidationError
+			require.ErrorAs(t, err, &fieldErr)
+			assert.Equal(t, tt.want, fieldErr.Errors)
+		})
+	}
+}
+
+func TestLoad_WithFieldValidator(t *testing.T) {
+	dir := t.TempDir()
+	testutils.CreateTempFile(t, filepath.Join(dir, ".speakeasy"), "gen.yaml", `configVersion: 2.0.0
+generation:
+  sdkClassName: My SDK
+go:
+  version: 1.0.0
+`)
+
+	_, err := Load(dir, WithLanguages("go"), WithValidateFunc(FieldValidator(false)))
+
+	var fieldErr *FieldValidationError
+	require.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, []FieldError{{Path: "generation.sdkClassName", Message: "Letters, numbers, or .-_ only"}}, fieldErr.Errors)
+	assert.EqualError(t, err, "gen.yaml has invalid fields:\ngeneration.sdkClassName: Letters, numbers, or .-_ only")
+}
@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/speakeasy-api/sdk-gen-config/workspace"
 )
 
 type fileStatus int
@@ -37,16 +39,19 @@ func getFileStatus(filePath string) fileStatus {
 	return fileStatusNotExists
 }
 
-func SanitizeFilePath(path string) string {
+// SanitizeFilePath expands a leading ~/ in path to the user's home directory.
+func SanitizeFilePath(path string, opts ...workspace.Option) string {
+	o := workspace.ApplyOptions(opts)
+
 	sanitizedPath := path
 	if strings.HasPrefix(path, "~/") {
-		homeDir, err := os.UserHomeDir()
+		homeDir, err := o.UserHomeDir()
 		if err != nil {
 			return path
 		}
 
 		sanitizedPath = filepath.Join(homeDir, path[2:])
-		if absPath, err := filepath.Abs(sanitizedPath); err == nil {
+		if absPath, err := o.Abs(sanitizedPath); err == nil {
 			sanitizedPath = absPath
 		}
 
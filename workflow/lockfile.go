@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
-	"os"
 	"path/filepath"
 
 	"github.com/speakeasy-api/sdk-gen-config/workspace"
@@ -38,10 +37,18 @@ type TargetLock struct {
 	CodeSamplesBlobDigest     string `yaml:"codeSamplesBlobDigest,omitempty"`
 }
 
-func LoadLockfile(dir string) (*LockFile, error) {
-	res, err := workspace.FindWorkspace(dir, workspace.FindWorkspaceOptions{
+func LoadLockfile(dir string, opts ...workspace.Option) (*LockFile, error) {
+	o := workspace.ApplyOptions(opts)
+
+	absDir, err := o.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	res, err := workspace.FindWorkspace(absDir, workspace.FindWorkspaceOptions{
 		FindFile:  workflowLockfile,
 		Recursive: true,
+		FS:        o.FS,
 	})
 	if err != nil {
 		if !errors.Is(err, fs.ErrNotExist) {
@@ -60,26 +67,40 @@ func LoadLockfile(dir string) (*LockFile, error) {
 
 // Save the workflow lockfile to the given directory, dir should generally be the root of the project,
 // and the lockfile will be saved to ${projectRoot}/.speakeasy/workflow.lock
-func SaveLockfile(dir string, lockfile *LockFile) error {
+func SaveLockfile(dir string, lockfile *LockFile, opts ...workspace.Option) error {
+	o := workspace.ApplyOptions(opts)
+
 	data, err := yaml.Marshal(lockfile)
 	if err != nil {
 		return fmt.Errorf("failed to marshal workflow lockfile: %w", err)
 	}
 
-	res, err := workspace.FindWorkspace(dir, workspace.FindWorkspaceOptions{
+	absDir, err := o.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	res, err := workspace.FindWorkspace(absDir, workspace.FindWorkspaceOptions{
 		FindFile:  workflowLockfile,
 		Recursive: true,
+		FS:        o.FS,
 	})
 	if err != nil {
 		if !errors.Is(err, fs.ErrNotExist) {
 			return err
 		}
 		res = &workspace.FindWorkspaceResult{
-			Path: filepath.Join(dir, workspace.SpeakeasyFolder, workflowLockfile),
+			Path: filepath.Join(absDir, workspace.SpeakeasyFolder, workflowLockfile),
 		}
 	}
 
-	if err := os.WriteFile(res.Path, data, 0o644); err != nil {
+	release, err := o.AcquireLock(filepath.Dir(res.Path))
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if err := o.WriteFile(res.Path, data, 0o644); err != nil {
 		return fmt.Errorf("failed to write workflow.lock: %w", err)
 	}
 
@@ -8,7 +8,7 @@ import (
 	"regexp"
 	"strings"
 
-	"github.com/a8m/envsubst"
+	"github.com/a8m/envsubst/parse"
 	"github.com/speakeasy-api/sdk-gen-config/workspace"
 	jsg "github.com/swaggest/jsonschema-go"
 )
@@ -106,10 +106,25 @@ type FallbackCodeSamples struct {
 
 type LocationString string
 
-func (l LocationString) Resolve() string {
+// envVarRegex matches the names of the environment variables referenced in a location.
+var envVarRegex = regexp.MustCompile(`\$\{?(\w+)`)
+
+// Resolve returns the location with any environment variables expanded.
+func (l LocationString) Resolve(opts ...workspace.Option) string {
 	s := string(l)
+	o := workspace.ApplyOptions(opts)
+
+	env := os.Environ()
+	if o.LookupEnv != nil {
+		env = nil
+		for _, m := range envVarRegex.FindAllStringSubmatch(s, -1) {
+			if value, ok := o.LookupEnv(m[1]); ok {
+				env = append(env, m[1]+"="+value)
+			}
+		}
+	}
 
-	expanded, err := envsubst.String(s)
+	expanded, err := parse.New("string", env, &parse.Restrictions{}).Parse(s)
 	if err != nil {
 		return s
 	}
@@ -290,10 +305,16 @@ func getExt(path string) string {
 	return ext
 }
 
-func GetTempDir() string {
-	wd, _ := os.Getwd()
+// GetTempDir returns the temp directory within the workspace containing the working directory.
+func GetTempDir(opts ...workspace.Option) string {
+	o := workspace.ApplyOptions(opts)
+
+	wd := o.WorkingDir
+	if wd == "" {
+		wd, _ = os.Getwd()
+	}
 
-	return workspace.FindWorkspaceTempDir(wd, workspace.FindWorkspaceOptions{})
+	return workspace.FindWorkspaceTempDir(wd, workspace.FindWorkspaceOptions{FS: o.FS})
 }
 
 func (s Source) GetTempMergeLocation() string {
@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
-	"os"
 	"path"
 	"path/filepath"
 	"slices"
@@ -68,10 +67,19 @@ func (Version) PrepareJSONSchema(schema *jsg.Schema) error {
 	return nil
 }
 
-func Load(dir string) (*Workflow, string, error) {
-	res, err := workspace.FindWorkspace(dir, workspace.FindWorkspaceOptions{
+// Load finds and loads the workflow.yaml for the project at dir, merging in any workflow.local.yaml found alongside it.
+func Load(dir string, opts ...workspace.Option) (*Workflow, string, error) {
+	o := workspace.ApplyOptions(opts)
+
+	absDir, err := o.Abs(dir)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	res, err := workspace.FindWorkspace(absDir, workspace.FindWorkspaceOptions{
 		FindFile:  workflowFile,
 		Recursive: true,
+		FS:        o.FS,
 	})
 	if err != nil {
 		if !errors.Is(err, fs.ErrNotExist) {
@@ -87,7 +95,7 @@ func Load(dir string) (*Workflow, string, error) {
 
 	// Look for a workflow.local.yaml file and merge any set values into the workflow
 	localPath := strings.Replace(res.Path, "workflow.yaml", "workflow.local.yaml", 1)
-	localData, err := os.ReadFile(localPath)
+	localData, err := o.ReadFile(localPath)
 	if err != nil {
 		if !errors.Is(err, fs.ErrNotExist) {
 			return nil, "", fmt.Errorf("failed to read workflow.local.yaml: %w", err)
@@ -104,26 +112,40 @@ func Load(dir string) (*Workflow, string, error) {
 }
 
 // Save the workflow to the given directory, dir should generally be the root of the project, and the workflow will be saved to ${projectRoot}/.speakeasy/workflow.yaml
-func Save(dir string, workflow *Workflow) error {
+func Save(dir string, workflow *Workflow, opts ...workspace.Option) error {
+	o := workspace.ApplyOptions(opts)
+
 	data, err := yaml.Marshal(workflow)
 	if err != nil {
 		return fmt.Errorf("failed to marshal workflow: %w", err)
 	}
 
-	res, err := workspace.FindWorkspace(dir, workspace.FindWorkspaceOptions{
+	absDir, err := o.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	res, err := workspace.FindWorkspace(absDir, workspace.FindWorkspaceOptions{
 		FindFile:  workflowFile,
 		Recursive: true,
+		FS:        o.FS,
 	})
 	if err != nil {
 		if !errors.Is(err, fs.ErrNotExist) {
 			return err
 		}
 		res = &workspace.FindWorkspaceResult{
-			Path: filepath.Join(dir, workspace.SpeakeasyFolder, "workflow.yaml"),
+			Path: filepath.Join(absDir, workspace.SpeakeasyFolder, "workflow.yaml"),
 		}
 	}
 
-	if err := os.WriteFile(res.Path, data, 0o644); err != nil {
+	release, err := o.AcquireLock(filepath.Dir(res.Path))
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if err := o.WriteFile(res.Path, data, 0o644); err != nil {
 		return fmt.Errorf("failed to write workflow.yaml: %w", err)
 	}
 
@@ -296,19 +318,19 @@ func (v Version) String() string {
 	return string(v)
 }
 
-func (w Workflow) Migrate() Workflow {
-	return w.migrate(false)
+func (w Workflow) Migrate(opts ...workspace.Option) Workflow {
+	return w.migrate(false, workspace.ApplyOptions(opts))
 }
 
-func (w Workflow) MigrateNoTelemetry() Workflow {
-	return w.migrate(true)
+func (w Workflow) MigrateNoTelemetry(opts ...workspace.Option) Workflow {
+	return w.migrate(true, workspace.ApplyOptions(opts))
 }
 
-func (w Workflow) migrate(telemetryDisabled bool) Workflow {
+func (w Workflow) migrate(telemetryDisabled bool, o *workspace.Options) Workflow {
 	// Backfill speakeasyVersion
 	if w.SpeakeasyVersion == "" {
 		// This is the pinned version from the GitHub action. If it's set, backfill using it.
-		if ghPinned := os.Getenv("PINNED_VERSION"); ghPinned != "" {
+		if ghPinned := o.Getenv("PINNED_VERSION"); ghPinned != "" {
 			w.SpeakeasyVersion = Version(ghPinned)
 		} else {
 			w.SpeakeasyVersion = "latest"
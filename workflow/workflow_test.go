@@ -2,6 +2,7 @@ package workflow_test
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"testing"
@@ -9,7 +10,9 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/speakeasy-api/openapi/pointer"
+	"github.com/speakeasy-api/sdk-gen-config/memfs"
 	"github.com/speakeasy-api/sdk-gen-config/workflow"
+	"github.com/speakeasy-api/sdk-gen-config/workspace"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -1112,3 +1115,95 @@ sources:
 		Secret: "$AUTH_TOKEN",
 	})
 }
+
+func TestWorkflow_LoadAndSave_FileSystem(t *testing.T) {
+	fsys := memfs.New()
+	require.NoError(t, fsys.WriteFile("/repo/.speakeasy/workflow.yaml", []byte(`workflowVersion: 1.0.0
+sources:
+  testSource:
+    inputs:
+      - location: ./openapi.yaml
+targets:
+  typescript:
+    target: typescript
+    source: testSource
+`), 0o644))
+	require.NoError(t, fsys.WriteFile("/repo/.speakeasy/workflow.local.yaml", []byte(`workflowVersion: 1.0.0
+sources:
+  testSource:
+    inputs:
+      - location: ./local.yaml
+`), 0o644))
+
+	opts := []workspace.Option{workspace.WithFileSystem(fsys), workspace.WithWorkingDir("/repo")}
+	snapshot := fsys.Snapshot()
+
+	workflowFile, workflowPath, err := workflow.Load("sdk", opts...)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("/repo", ".speakeasy", "workflow.yaml"), workflowPath)
+	assert.Equal(t, workflow.LocationString("./local.yaml"), workflowFile.Sources["testSource"].Inputs[0].Location)
+
+	workflowFile.SpeakeasyVersion = "1.2.3"
+	require.NoError(t, workflow.Save(".", workflowFile, opts...))
+
+	require.NoError(t, workflow.SaveLockfile(".", &workflow.LockFile{SpeakeasyVersion: "1.2.3"}, opts...))
+	lockFile, err := workflow.LoadLockfile(".", opts...)
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3", lockFile.SpeakeasyVersion)
+
+	changes := fsys.Diff(snapshot)
+	require.Len(t, changes, 2)
+	assert.Equal(t, "repo/.speakeasy/workflow.lock", changes[0].Path)
+	assert.Equal(t, "repo/.speakeasy/workflow.yaml", changes[1].Path)
+	assert.Contains(t, string(changes[1].After), "speakeasyVersion: 1.2.3")
+
+	// Saving requires a writable filesystem
+	readOnly := struct{ fs.StatFS }{fsys}
+	err = workflow.Save(".", workflowFile, workspace.WithFileSystem(readOnly), workspace.WithWorkingDir("/repo"))
+	assert.ErrorIs(t, err, os.ErrPermission)
+}
+
+func TestWorkflow_Options_Environment(t *testing.T) {
+	// The host environment must not be used when an environment is provided
+	t.Setenv("SPEC_DIR", "/host/specs")
+	t.Setenv("PINNED_VERSION", "9.9.9")
+	t.Setenv("HOME", "/host/home")
+
+	env := map[string]string{
+		"SPEC_DIR":       "/specs",
+		"PINNED_VERSION": "1.2.3",
+		"HOME":           "/home/fake",
+		"USERPROFILE":    "/home/fake",
+		"home":           "/home/fake",
+	}
+	lookupEnv := func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+
+	fsys := memfs.New()
+	require.NoError(t, fsys.WriteFile("/repo/.speakeasy/workflow.yaml", []byte(`workflowVersion: 1.0.0
+sources:
+  testSource:
+    inputs:
+      - location: ${SPEC_DIR}/openapi.yaml
+targets:
+  typescript:
+    target: typescript
+    source: testSource
+`), 0o644))
+
+	opts := []workspace.Option{
+		workspace.WithFileSystem(fsys),
+		workspace.WithWorkingDir("/repo"),
+		workspace.WithLookupEnv(lookupEnv),
+	}
+
+	workflowFile, _, err := workflow.Load(".", opts...)
+	require.NoError(t, err)
+
+	assert.Equal(t, "/specs/openapi.yaml", workflowFile.Sources["testSource"].Inputs[0].Location.Resolve(opts...))
+	assert.Equal(t, workflow.Version("1.2.3"), workflowFile.Migrate(opts...).SpeakeasyVersion)
+	assert.Equal(t, filepath.Join("/home/fake", "openapi.yaml"), workflow.SanitizeFilePath("~/openapi.yaml", opts...))
+	assert.Equal(t, filepath.Join("/repo", ".speakeasy", "temp"), workflow.GetTempDir(opts...))
+}
@@ -0,0 +1,213 @@
+package workspace
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LockFileName is the name of the advisory lock file created in the .speakeasy directory.
+const LockFileName = ".lock"
+
+var ErrLocked = errors.New("workspace is locked")
+
+const (
+	lockPollInterval = 50 * time.Millisecond
+	// Lock files that can't be read are only treated as stale after this long, as the holder may still be writing them
+	lockCorruptAfter = 10 * time.Second
+	// Appended to the lock file name while a stale lock is removed
+	takeoverSuffix = ".takeover"
+)
+
+// LockHolder describes the process holding a Lock.
+type LockHolder struct {
+	PID      int       `json:"pid"`
+	Hostname string    `json:"hostname"`
+	Acquired time.Time `json:"acquired"`
+}
+
+// Lock is an advisory lock on a workspace directory such as .speakeasy, held by creating a lock file in it.
+// It only excludes other processes that also acquire the lock.
+type Lock struct {
+	path   string
+	holder LockHolder
+}
+
+// AcquireLock locks dir, waiting up to timeout for any other holder to release it. Locks left behind by processes
+// that have exited on this host are removed. If dir remains locked an error wrapping ErrLocked names the holder.
+func AcquireLock(dir string, timeout time.Duration) (*Lock, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	hostname, _ := os.Hostname()
+	l := &Lock{
+		path: filepath.Join(dir, LockFileName),
+		holder: LockHolder{
+			PID:      os.Getpid(),
+			Hostname: hostname,
+		},
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		l.holder.Acquired = time.Now().UTC()
+		created, err := l.tryCreate()
+		if err != nil {
+			return nil, err
+		}
+		if created {
+			return l, nil
+		}
+
+		holder, data, stale := l.current(hostname)
+		if stale {
+			removed, err := l.removeStale(data)
+			if err != nil {
+				return nil, err
+			}
+			// Otherwise wait for another process to finish removing it, or check the lock that replaced it
+			if removed {
+				continue
+			}
+		}
+
+		if !time.Now().Before(deadline) {
+			if holder == nil {
+				return nil, fmt.Errorf("%w: %s is held by an unknown process", ErrLocked, l.path)
+			}
+			return nil, fmt.Errorf("%w: %s is held by process %d on %s since %s", ErrLocked, l.path, holder.PID, holder.Hostname, holder.Acquired.Format(time.RFC3339))
+		}
+
+		time.Sleep(min(lockPollInterval, time.Until(deadline)))
+	}
+}
+
+// tryCreate creates the lock file, returning false if it already exists.
+func (l *Lock) tryCreate() (bool, error) {
+	data, err := json.Marshal(l.holder)
+	if err != nil {
+		return false, err
+	}
+
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to create lock %s: %w", l.path, err)
+	}
+
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(l.path)
+		return false, fmt.Errorf("failed to write lock %s: %w", l.path, err)
+	}
+
+	return true, nil
+}
+
+// current returns the holder of the existing lock file along with its contents, and whether it is stale and can be
+// removed. A nil data with stale set means the lock file no longer exists.
+func (l *Lock) current(hostname string) (*LockHolder, []byte, bool) {
+	info, err := os.Stat(l.path)
+	if err != nil {
+		// Released since we tried to create it
+		return nil, nil, errors.Is(err, fs.ErrNotExist)
+	}
+
+	data, err := os.ReadFile(l.path)
+	if err != nil {
+		return nil, nil, errors.Is(err, fs.ErrNotExist)
+	}
+
+	var holder LockHolder
+	if err := json.Unmarshal(data, &holder); err != nil || holder.PID == 0 {
+		return nil, data, time.Since(info.ModTime()) > lockCorruptAfter
+	}
+
+	if holder.Hostname == hostname && !processExists(holder.PID) {
+		return &holder, data, true
+	}
+
+	return &holder, data, false
+}
+
+// removeStale removes the lock file if it still contains data, which was found to be stale, and returns true if the
+// lock file is gone so it can be created again. Removals are serialized by a takeover file, so a process that found
+// the same stale lock can't remove a fresh lock created after it was removed.
+func (l *Lock) removeStale(data []byte) (bool, error) {
+	if data == nil {
+		return true, nil
+	}
+
+	takeover := l.path + takeoverSuffix
+	f, err := os.OpenFile(takeover, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		if !errors.Is(err, fs.ErrExist) {
+			return false, fmt.Errorf("failed to remove stale lock %s: %w", l.path, err)
+		}
+
+		// Another process is removing the lock, unless it exited while doing so
+		if info, err := os.Stat(takeover); err == nil && time.Since(info.ModTime()) > lockCorruptAfter {
+			_ = os.Remove(takeover)
+		}
+		return false, nil
+	}
+	_ = f.Close()
+	defer os.Remove(takeover)
+
+	current, err := os.ReadFile(l.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return true, nil
+		}
+		return false, fmt.Errorf("failed to remove stale lock %s: %w", l.path, err)
+	}
+
+	// The lock was replaced since it was found to be stale
+	if !bytes.Equal(current, data) {
+		return false, nil
+	}
+
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return false, fmt.Errorf("failed to remove stale lock %s: %w", l.path, err)
+	}
+
+	return true, nil
+}
+
+// Holder returns the process holding the lock.
+func (l *Lock) Holder() LockHolder {
+	return l.holder
+}
+
+// Release unlocks the directory. The lock file is only removed if it still belongs to this lock.
+func (l *Lock) Release() error {
+	data, err := os.ReadFile(l.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to release lock %s: %w", l.path, err)
+	}
+
+	var holder LockHolder
+	if err := json.Unmarshal(data, &holder); err != nil || !holder.Acquired.Equal(l.holder.Acquired) || holder.PID != l.holder.PID {
+		return nil
+	}
+
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to release lock %s: %w", l.path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,316 @@
+package workspace
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "gen.lock")
+
+	if err := WriteFileAtomic(path, []byte("first"), 0o666); err != nil {
+		t.Fatalf("WriteFileAtomic failed: %v", err)
+	}
+
+	// New files get the same mode as os.WriteFile would give them under the current umask
+	expected := filepath.Join(dir, "expected")
+	if err := os.WriteFile(expected, nil, 0o666); err != nil {
+		t.Fatal(err)
+	}
+	assertMode(t, path, modeOf(t, expected))
+
+	if err := os.Chmod(path, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFileAtomic(path, []byte("second"), 0o666); err != nil {
+		t.Fatalf("WriteFileAtomic failed: %v", err)
+	}
+	assertMode(t, path, 0o600)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "second" {
+		t.Errorf("expected file to contain %q, got %q", "second", data)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".tmp") {
+			t.Errorf("temporary file %s was left behind", e.Name())
+		}
+	}
+
+	if err := WriteFileAtomic(filepath.Join(dir, "missing", "gen.lock"), []byte("data"), 0o666); err == nil {
+		t.Error("expected writing to a missing directory to fail")
+	}
+}
+
+func TestAcquireLock(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), SpeakeasyFolder)
+
+	lock, err := AcquireLock(dir, 0)
+	if err != nil {
+		t.Fatalf("AcquireLock failed: %v", err)
+	}
+	if lock.Holder().PID != os.Getpid() {
+		t.Errorf("expected lock to be held by %d, got %d", os.Getpid(), lock.Holder().PID)
+	}
+
+	start := time.Now()
+	_, err = AcquireLock(dir, 200*time.Millisecond)
+	if !errors.Is(err, ErrLocked) {
+		t.Fatalf("expected ErrLocked, got %v", err)
+	}
+	if time.Since(start) < 200*time.Millisecond {
+		t.Errorf("expected AcquireLock to wait for the timeout")
+	}
+	if !strings.Contains(err.Error(), fmt.Sprintf("held by process %d", os.Getpid())) {
+		t.Errorf("expected error to name the holder, got %v", err)
+	}
+
+	// The lock can be acquired once it is released by another goroutine
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_ = lock.Release()
+	}()
+	second, err := AcquireLock(dir, 5*time.Second)
+	if err != nil {
+		t.Fatalf("AcquireLock failed: %v", err)
+	}
+
+	// Releasing a lock that was already released doesn't remove another holder's lock
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, LockFileName)); err != nil {
+		t.Errorf("expected lock file to remain: %v", err)
+	}
+
+	if err := second.Release(); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, LockFileName)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected lock file to be removed, got %v", err)
+	}
+}
+
+func TestAcquireLock_Stale(t *testing.T) {
+	dir := t.TempDir()
+	hostname, _ := os.Hostname()
+
+	tests := []struct {
+		name    string
+		holder  LockHolder
+		wantErr bool
+	}{
+		{
+			name:   "exited process on this host",
+			holder: LockHolder{PID: exitedPID(t), Hostname: hostname},
+		},
+		{
+			name:    "process on another host",
+			holder:  LockHolder{PID: exitedPID(t), Hostname: hostname + "-other"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.holder)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, LockFileName), data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { _ = os.Remove(filepath.Join(dir, LockFileName)) })
+
+			lock, err := AcquireLock(dir, 0)
+			if tt.wantErr {
+				if !errors.Is(err, ErrLocked) {
+					t.Fatalf("expected ErrLocked, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AcquireLock failed: %v", err)
+			}
+			_ = lock.Release()
+		})
+	}
+}
+
+func TestAcquireLock_StaleRace(t *testing.T) {
+	dir := t.TempDir()
+	hostname, _ := os.Hostname()
+
+	data, err := json.Marshal(LockHolder{PID: exitedPID(t), Hostname: hostname})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for round := 0; round < 5; round++ {
+		if err := os.WriteFile(filepath.Join(dir, LockFileName), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		// Every goroutine finds the same stale lock, only one may hold the lock at a time
+		var holders, maxHolders atomic.Int32
+		var wg sync.WaitGroup
+		errs := make(chan error, 8)
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				lock, err := AcquireLock(dir, 5*time.Second)
+				if err != nil {
+					errs <- err
+					return
+				}
+
+				n := holders.Add(1)
+				for {
+					m := maxHolders.Load()
+					if n <= m || maxHolders.CompareAndSwap(m, n) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				holders.Add(-1)
+
+				if err := lock.Release(); err != nil {
+					errs <- err
+				}
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			t.Fatalf("round %d: %v", round, err)
+		}
+		if n := maxHolders.Load(); n != 1 {
+			t.Fatalf("round %d: expected one holder at a time, got %d", round, n)
+		}
+		if _, err := os.Stat(filepath.Join(dir, LockFileName+takeoverSuffix)); !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("round %d: expected takeover file to be removed, got %v", round, err)
+		}
+	}
+}
+
+func TestAcquireLock_StaleReplaced(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, LockFileName)
+	hostname, _ := os.Hostname()
+
+	data, err := json.Marshal(LockHolder{PID: exitedPID(t), Hostname: hostname})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	l := &Lock{path: path}
+	_, judged, stale := l.current(hostname)
+	if !stale {
+		t.Fatal("expected lock to be stale")
+	}
+
+	// Another process removes the stale lock and acquires it before this one removes it
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	fresh, err := AcquireLock(dir, 0)
+	if err != nil {
+		t.Fatalf("AcquireLock failed: %v", err)
+	}
+	defer fresh.Release()
+
+	if _, err := l.removeStale(judged); err != nil {
+		t.Fatalf("removeStale failed: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("expected the fresh lock to remain: %v", err)
+	}
+	if _, err := AcquireLock(dir, 0); !errors.Is(err, ErrLocked) {
+		t.Errorf("expected ErrLocked, got %v", err)
+	}
+}
+
+func TestAcquireLock_StaleTakeoverTimeout(t *testing.T) {
+	dir := t.TempDir()
+	hostname, _ := os.Hostname()
+
+	data, err := json.Marshal(LockHolder{PID: exitedPID(t), Hostname: hostname})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, LockFileName), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another process is removing the stale lock, so this one waits for it up to the timeout
+	if err := os.WriteFile(filepath.Join(dir, LockFileName+takeoverSuffix), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	_, err = AcquireLock(dir, 200*time.Millisecond)
+	if !errors.Is(err, ErrLocked) {
+		t.Fatalf("expected ErrLocked, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected AcquireLock to give up after the timeout, took %s", elapsed)
+	}
+}
+
+// exitedPID returns the PID of a process that has exited.
+func exitedPID(t *testing.T) int {
+	t.Helper()
+
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := os.StartProcess(exe, []string{exe, "-test.run=^$"}, &os.ProcAttr{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	return p.Pid
+}
+
+func modeOf(t *testing.T, path string) os.FileMode {
+	t.Helper()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info.Mode().Perm()
+}
+
+func assertMode(t *testing.T, path string, want os.FileMode) {
+	t.Helper()
+
+	if got := modeOf(t, path); got != want {
+		t.Errorf("expected %s to have mode %s, got %s", filepath.Base(path), want, got)
+	}
+}
@@ -0,0 +1,150 @@
+package workspace
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// WriteFS is a filesystem that files can also be written to, such as config.FS.
+type WriteFS interface {
+	FS
+	WriteFile(name string, data []byte, perm os.FileMode) error
+}
+
+// Option configures where workspace files such as workflow.yaml and lint.yaml are loaded from and saved to.
+type Option func(*Options)
+
+// Options holds the environment that workspace files are loaded in, defaulting to the OS.
+type Options struct {
+	FS         FS                              // The filesystem to read and write files with, files are written only if it implements WriteFS
+	WorkingDir string                          // The directory relative paths are resolved against
+	HomeDir    string                          // The user's home directory
+	LookupEnv  func(key string) (string, bool) // Looks up environment variables
+
+	Locking     bool          // Lock the workspace directory while saving files
+	LockTimeout time.Duration // How long to wait for another process to release the lock
+}
+
+// WithFileSystem reads and writes files using fs instead of the OS.
+func WithFileSystem(fs FS) Option {
+	return func(o *Options) {
+		o.FS = fs
+	}
+}
+
+// WithWorkingDir resolves relative paths against dir instead of the current working directory.
+func WithWorkingDir(dir string) Option {
+	return func(o *Options) {
+		o.WorkingDir = dir
+	}
+}
+
+// WithHomeDir sets the user's home directory instead of looking it up from the environment.
+func WithHomeDir(dir string) Option {
+	return func(o *Options) {
+		o.HomeDir = dir
+	}
+}
+
+// WithLookupEnv looks up environment variables using lookupEnv instead of os.LookupEnv.
+func WithLookupEnv(lookupEnv func(key string) (string, bool)) Option {
+	return func(o *Options) {
+		o.LookupEnv = lookupEnv
+	}
+}
+
+// WithLock holds an advisory lock on the workspace directory while files are saved, waiting up to timeout for other
+// processes to release it. Locks are only taken when writing to the OS filesystem.
+func WithLock(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.Locking = true
+		o.LockTimeout = timeout
+	}
+}
+
+// ApplyOptions returns the Options resulting from opts.
+func ApplyOptions(opts []Option) *Options {
+	o := &Options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Abs returns an absolute version of path, resolving relative paths against the working directory.
+func (o *Options) Abs(path string) (string, error) {
+	if o.WorkingDir == "" || filepath.IsAbs(path) {
+		return filepath.Abs(path)
+	}
+	return filepath.Abs(filepath.Join(o.WorkingDir, path))
+}
+
+// Getenv returns the value of the environment variable key, or an empty string if it isn't set.
+func (o *Options) Getenv(key string) string {
+	lookupEnv := o.LookupEnv
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+	value, _ := lookupEnv(key)
+	return value
+}
+
+// UserHomeDir returns the user's home directory, following the same rules as os.UserHomeDir using the configured
+// environment.
+func (o *Options) UserHomeDir() (string, error) {
+	if o.HomeDir != "" {
+		return o.HomeDir, nil
+	}
+	if o.LookupEnv == nil {
+		return os.UserHomeDir()
+	}
+
+	key := "HOME"
+	switch runtime.GOOS {
+	case "windows":
+		key = "USERPROFILE"
+	case "plan9":
+		key = "home"
+	}
+
+	if dir := o.Getenv(key); dir != "" {
+		return dir, nil
+	}
+	return "", fmt.Errorf("$%s is not defined", key)
+}
+
+// AcquireLock locks dir if locking is enabled, returning a function to release it.
+func (o *Options) AcquireLock(dir string) (func(), error) {
+	if !o.Locking || o.FS != nil {
+		return func() {}, nil
+	}
+
+	lock, err := AcquireLock(dir, o.LockTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return func() { _ = lock.Release() }, nil
+}
+
+// ReadFile reads the file at path.
+func (o *Options) ReadFile(path string) ([]byte, error) {
+	return readFileFunc(path, o.FS)
+}
+
+// WriteFile writes data to the file at path, atomically when writing to the OS, returning an error if the filesystem
+// is read only.
+func (o *Options) WriteFile(path string, data []byte, perm os.FileMode) error {
+	if o.FS == nil {
+		return WriteFileAtomic(path, data, perm)
+	}
+
+	wfs, ok := o.FS.(WriteFS)
+	if !ok {
+		return &fs.PathError{Op: "write", Path: path, Err: fs.ErrPermission}
+	}
+	return wfs.WriteFile(path, data, perm)
+}
@@ -0,0 +1,14 @@
+//go:build unix
+
+package workspace
+
+import (
+	"errors"
+	"syscall"
+)
+
+// processExists returns true if a process with pid is running on this host.
+func processExists(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
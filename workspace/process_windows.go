@@ -0,0 +1,16 @@
+//go:build windows
+
+package workspace
+
+import "os"
+
+// processExists returns true if a process with pid is running on this host.
+func processExists(pid int) bool {
+	// On Windows FindProcess opens a handle to the process, which fails if it has exited
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = p.Release()
+	return true
+}
@@ -15,7 +15,7 @@ const (
 	GenFolder       = ".gen"
 )
 
-var GitIgnoreEntries = []string{"logs/", "temp/", "reports/"}
+var GitIgnoreEntries = []string{"logs/", "temp/", "reports/", LockFileName}
 
 // EnsureSpeakeasyDir creates .speakeasy/ inside parentDir with a .gitignore containing GitIgnoreEntries.
 func EnsureSpeakeasyDir(parentDir string) error {
@@ -52,7 +52,7 @@ func ensureGitIgnore(dir string) error {
 
 	content += strings.Join(toAdd, "\n") + "\n"
 
-	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+	if err := WriteFileAtomic(path, []byte(content), 0o644); err != nil {
 		return fmt.Errorf("failed to write %s: %w", path, err)
 	}
 	return nil